@@ -0,0 +1,204 @@
+package chaoskube
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// workloadCache caches workload lookups for the duration of a single run, so that resolving
+// the owners of many pods doesn't result in one API call per pod.
+type workloadCache struct {
+	client  kubernetes.Interface
+	entries map[string]workloadCacheEntry
+}
+
+// workloadCacheEntry is the cached result of a single workload lookup.
+type workloadCacheEntry struct {
+	obj runtime.Object
+	err error
+}
+
+// newWorkloadCache returns an empty workloadCache using the given client for lookups.
+func newWorkloadCache(client kubernetes.Interface) *workloadCache {
+	return &workloadCache{
+		client:  client,
+		entries: map[string]workloadCacheEntry{},
+	}
+}
+
+// get returns the workload of the given kind, namespace and name.
+// Both the workload and any error are cached, so each workload is looked up at most once.
+func (wc *workloadCache) get(kind, namespace, name string) (runtime.Object, error) {
+	key := fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+
+	if entry, ok := wc.entries[key]; ok {
+		return entry.obj, entry.err
+	}
+
+	var (
+		obj runtime.Object
+		err error
+	)
+
+	options := metav1.GetOptions{}
+
+	switch kind {
+	case "Deployment":
+		obj, err = wc.client.AppsV1().Deployments(namespace).Get(name, options)
+	case "ReplicaSet":
+		obj, err = wc.client.AppsV1().ReplicaSets(namespace).Get(name, options)
+	case "StatefulSet":
+		obj, err = wc.client.AppsV1().StatefulSets(namespace).Get(name, options)
+	case "DaemonSet":
+		obj, err = wc.client.AppsV1().DaemonSets(namespace).Get(name, options)
+	case "ReplicationController":
+		obj, err = wc.client.CoreV1().ReplicationControllers(namespace).Get(name, options)
+	default:
+		err = fmt.Errorf("unsupported workload kind: %s", kind)
+	}
+
+	if err != nil {
+		obj = nil
+	}
+
+	wc.entries[key] = workloadCacheEntry{obj: obj, err: err}
+
+	return obj, err
+}
+
+// workload looks up the workload of the given kind, namespace and name through the cache of
+// the current run.
+func (c *Chaoskube) workload(kind, namespace, name string) (runtime.Object, error) {
+	if c.workloads == nil {
+		c.workloads = newWorkloadCache(c.Client)
+	}
+	return c.workloads.get(kind, namespace, name)
+}
+
+// namespaceCache caches the cluster's namespaces for the duration of a single run. All
+// namespaces are listed at once on first use, so that checking the namespaces of many pods
+// takes a single API call.
+type namespaceCache struct {
+	client     kubernetes.Interface
+	namespaces map[string]*v1.Namespace
+	err        error
+}
+
+// newNamespaceCache returns an empty namespaceCache using the given client for lookups.
+func newNamespaceCache(client kubernetes.Interface) *namespaceCache {
+	return &namespaceCache{client: client}
+}
+
+// get returns the namespace with the given name.
+func (nc *namespaceCache) get(name string) (*v1.Namespace, error) {
+	if nc.namespaces == nil && nc.err == nil {
+		nc.namespaces, nc.err = nc.list()
+	}
+	if nc.err != nil {
+		return nil, nc.err
+	}
+
+	namespace, ok := nc.namespaces[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(v1.Resource("namespaces"), name)
+	}
+
+	return namespace, nil
+}
+
+// list fetches all namespaces, keyed by name, in pages of listPageSize namespaces.
+func (nc *namespaceCache) list() (map[string]*v1.Namespace, error) {
+	namespaces := map[string]*v1.Namespace{}
+
+	listOptions := metav1.ListOptions{Limit: listPageSize}
+
+	for {
+		namespaceList, err := nc.client.CoreV1().Namespaces().List(listOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range namespaceList.Items {
+			namespaces[namespaceList.Items[i].Name] = &namespaceList.Items[i]
+		}
+
+		if namespaceList.Continue == "" {
+			return namespaces, nil
+		}
+		listOptions.Continue = namespaceList.Continue
+	}
+}
+
+// nodeCache caches the cluster's nodes for the duration of a single run. All nodes are listed
+// at once on first use, so that joining many pods with their nodes takes a single API call.
+type nodeCache struct {
+	client kubernetes.Interface
+	nodes  map[string]*v1.Node
+	err    error
+}
+
+// newNodeCache returns an empty nodeCache using the given client for lookups.
+func newNodeCache(client kubernetes.Interface) *nodeCache {
+	return &nodeCache{client: client}
+}
+
+// get returns the node with the given name.
+func (nc *nodeCache) get(name string) (*v1.Node, error) {
+	if nc.nodes == nil && nc.err == nil {
+		nc.nodes, nc.err = nc.list()
+	}
+	if nc.err != nil {
+		return nil, nc.err
+	}
+
+	node, ok := nc.nodes[name]
+	if !ok {
+		return nil, apierrors.NewNotFound(v1.Resource("nodes"), name)
+	}
+
+	return node, nil
+}
+
+// list fetches all nodes, keyed by name, in pages of listPageSize nodes.
+func (nc *nodeCache) list() (map[string]*v1.Node, error) {
+	nodes := map[string]*v1.Node{}
+
+	listOptions := metav1.ListOptions{Limit: listPageSize}
+
+	for {
+		nodeList, err := nc.client.CoreV1().Nodes().List(listOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range nodeList.Items {
+			nodes[nodeList.Items[i].Name] = &nodeList.Items[i]
+		}
+
+		if nodeList.Continue == "" {
+			return nodes, nil
+		}
+		listOptions.Continue = nodeList.Continue
+	}
+}
+
+// node looks up the node with the given name through the cache of the current run.
+func (c *Chaoskube) node(name string) (*v1.Node, error) {
+	if c.nodes == nil {
+		c.nodes = newNodeCache(c.Client)
+	}
+	return c.nodes.get(name)
+}
+
+// namespace looks up the namespace with the given name through the cache of the current run.
+func (c *Chaoskube) namespace(name string) (*v1.Namespace, error) {
+	if c.namespaces == nil {
+		c.namespaces = newNamespaceCache(c.Client)
+	}
+	return c.namespaces.get(name)
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-go/statsd"
@@ -13,10 +15,14 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	v1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 
 	"github.com/metrosystems-cpe/chaoskube/util"
 )
@@ -31,12 +37,38 @@ type Chaoskube struct {
 	Annotations labels.Selector
 	// a namespace selector which restricts the pods to choose from
 	Namespaces labels.Selector
+	// a selector on the labels of Namespace objects which restricts the pods to choose from,
+	// nil selects all namespaces
+	NamespaceLabels labels.Selector
+	// glob or regexp patterns on namespace names which restrict the pods to choose from
+	NamespacePatterns []util.NamePattern
+	// glob or regexp patterns on pod names which restrict the pods to choose from
+	PodNamePatterns []util.NamePattern
+	// a selector on the labels of the nodes which restricts the pods to choose from, nil selects all nodes
+	NodeLabels labels.Selector
+	// a selector on the taints of the nodes which restricts the pods to choose from, nil selects all nodes
+	NodeTaints labels.Selector
+	// the kinds of controllers whose pods are never terminated, e.g. DaemonSet
+	ExcludedOwnerKinds []string
+	// the priority classes whose pods are never terminated, e.g. system-cluster-critical
+	ExcludedPriorityClasses []string
+	// the QoS classes whose pods are never terminated
+	ExcludedQOSClasses []v1.PodQOSClass
+	// the namespace and name of chaoskube's own pod, which is never terminated along with its peers
+	SelfNamespace string
+	SelfName      string
 	// a list of weekdays when termination is suspended
 	ExcludedWeekdays []time.Weekday
 	// a list of time periods of a day when termination is suspended
 	ExcludedTimesOfDay []util.TimePeriod
 	// a list of days of a year when termination is suspended
 	ExcludedDaysOfYear []time.Time
+	// a list of named ranges of days when termination is suspended, e.g. a change freeze
+	Blackouts []util.Blackout
+	// a list of weekday-specific time periods when termination is suspended, e.g. Fri 14:00-23:59
+	ExcludedWindows []util.TimeWindow
+	// a list of time windows outside of which termination is suspended, empty allows any time
+	AllowedWindows []util.TimeWindow
 	// the timezone to apply when detecting the current weekday
 	Timezone *time.Location
 	// an instance of logrus.StdLogger to write log messages to
@@ -44,14 +76,91 @@ type Chaoskube struct {
 	// dry run will not allow any pod terminations
 	DryRun bool
 	// a function to retrieve the current time
-	Now      func() time.Time
-	DDEvents bool
-	DDClient *statsd.Client
+	Now func() time.Time
+	// the mean time between runs unless there's a schedule
+	Interval time.Duration
+	// how the time between runs is distributed around the interval, e.g. jitter, empty is fixed
+	IntervalDistribution string
+	// the maximum deviation from the interval in percent when jittering
+	IntervalJitter int
+	// the bounds of the time between runs, zero means unbounded
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	// a cron schedule on which to terminate victims, evaluated in the timezone, nil runs in fixed intervals
+	Schedule *util.Schedule
+	// the failure domain to terminate all candidates of at once, either "node" or a node label
+	// such as topology.kubernetes.io/zone, empty terminates individual pods
+	FailureDomain string
+	// the source of randomness for picking victims, seed it to reproduce a sequence of picks
+	Rand *rand.Rand
+	// the source of randomness for varying intervals, kept apart from Rand so that interval
+	// settings don't change the sequence of victims
+	IntervalRand *rand.Rand
+	DDEvents     bool
+	DDClient     *statsd.Client
+	// an annotation key holding a pod's relative odds of being picked, e.g. chaos.weight
+	// an empty key disables weighting and every candidate is equally likely
+	WeightAnnotation string
+	// the weight given to pods without a valid weight annotation
+	DefaultWeight int
+	// the number of distinct pods to terminate per run
+	VictimCount int
+	// the percentage of candidates to terminate per run, overrides VictimCount if set
+	VictimPercentage int
+	// the maximum number of pods to terminate per run, zero means no limit
+	MaxVictims int
+	// whether to pick a workload first and then a pod within it
+	GroupByOwner bool
+	// whether to evict pods through the Eviction API in order to respect PodDisruptionBudgets
+	UseEviction bool
+	// counts of noteworthy events such as evictions blocked by a PodDisruptionBudget
+	Stats *Stats
+	// how to terminate victims unless overridden by a pod's annotation
+	TerminationMode TerminationMode
+	// the minimum time a pod must be running for before it can be terminated
+	MinimumAge time.Duration
+	// the pod phases to choose from, empty means any phase
+	Phases []v1.PodPhase
+	// whether to include pods that aren't ready
+	IncludeUnready bool
+	// whether to spare pods whose workload is degraded or in the middle of a rollout
+	CheckWorkloadHealth bool
+	// the minimum number of replicas a pod's workload must desire for the pod to be a candidate
+	MinReplicas int
+	// whether to exclude pods that aren't controlled by any workload
+	ExcludeBarePods bool
+	// the time a workload is spared after one of its pods was terminated
+	WorkloadCooldown time.Duration
+	// the time the pods on a node are spared after one of them was terminated
+	NodeCooldown time.Duration
+	// the recently terminated workloads and nodes
+	History *History
+	// a local pod cache to list candidates from, nil lists pods from the API server
+	PodLister corelisters.PodLister
+	// the workloads looked up during the current run
+	workloads *workloadCache
+	// the nodes looked up during the current run
+	nodes *nodeCache
+	// the namespaces looked up during the current run
+	namespaces *namespaceCache
 }
 
+// MinimumAgeAnnotation is the pod annotation that overrides the configured minimum age, e.g. 1h.
+const MinimumAgeAnnotation = "chaos.minimum-age"
+
+const (
+	// MaxWeight is the highest weight a pod can have, higher weights are capped
+	MaxWeight = 1000000
+
+	// maxInt is the largest value of an int on this platform
+	maxInt = int(^uint(0) >> 1)
+)
+
 var (
 	// errPodNotFound is returned when no victim could be found
 	errPodNotFound = errors.New("pod not found")
+	// errPodProtected is returned when a PodDisruptionBudget prevents a victim's eviction
+	errPodProtected = errors.New("pod protected by disruption budget")
 	// msgVictimNotFound is the log message when no victim was found
 	msgVictimNotFound = "no victim found"
 	// msgWeekdayExcluded is the log message when termination is suspended due to the weekday filter
@@ -60,6 +169,14 @@ var (
 	msgTimeOfDayExcluded = "time of day excluded"
 	// msgDayOfYearExcluded is the log message when termination is suspended due to the day of year filter
 	msgDayOfYearExcluded = "day of year excluded"
+	// msgEvictionBlocked is the log message when victims were skipped due to a PodDisruptionBudget
+	msgEvictionBlocked = "evictions blocked by disruption budget"
+	// msgWorkloadUnhealthy is the log message when a victim is skipped due to its workload's health
+	msgWorkloadUnhealthy = "skipping pod of unhealthy workload"
+	// msgFailureDomainPicked is the log message when a failure domain was picked
+	msgFailureDomainPicked = "terminating failure domain"
+	// msgFailureDomainTerminated is the log message listing the victims of a failure domain
+	msgFailureDomainTerminated = "terminated failure domain"
 )
 
 // New returns a new instance of Chaoskube. It expects:
@@ -84,14 +201,25 @@ func New(client kubernetes.Interface, labels, annotations, namespaces labels.Sel
 		Now:                time.Now,
 		DDEvents:           ddEvents,
 		DDClient:           ddClient,
+		VictimCount:        1,
+		Phases:             []v1.PodPhase{v1.PodRunning},
+		History:            NewHistory(),
+		Stats:              NewStats(),
+		Rand:               rand.New(rand.NewSource(time.Now().UnixNano())),
+		IntervalRand:       rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
-// TerminateVictim picks and deletes a victim.
+// TerminateVictim picks and deletes one or more victims.
 // It respects the configured excluded weekdays, times of day and days of a year filters.
 func (c *Chaoskube) TerminateVictim() error {
 	now := c.Now().In(c.Timezone)
 
+	if !c.allowed(now) {
+		c.Logger.Debugf("Time [%s %s] is outside the allowed windows", now.Weekday(), now.Format(util.Kitchen24))
+		return nil
+	}
+
 	for _, wd := range c.ExcludedWeekdays {
 		if wd == now.Weekday() {
 			// c.Logger.WithField("weekday", now.Weekday()).Debug(msgWeekdayExcluded)
@@ -114,16 +242,171 @@ func (c *Chaoskube) TerminateVictim() error {
 		}
 	}
 
-	victim, err := c.Victim()
-	if err == errPodNotFound {
-		c.Logger.Debug(msgVictimNotFound)
-		return nil
+	for _, blackout := range c.Blackouts {
+		if blackout.Includes(now) {
+			c.Logger.Infof("Day [%s] is within blackout [%s]", now.Format(util.Date), blackout)
+			return nil
+		}
+	}
+
+	for _, window := range c.ExcludedWindows {
+		if window.Includes(now) {
+			c.Logger.Debugf("Time [%s %s] is excluded by window [%s]", now.Weekday(), now.Format(util.Kitchen24), window)
+			return nil
+		}
 	}
+
+	pods, err := c.Candidates()
 	if err != nil {
 		return err
 	}
 
-	return c.DeletePod(victim)
+	c.Logger.Debugf("Found [%d] candidates", len(pods))
+
+	if c.FailureDomain != "" {
+		return c.terminateFailureDomain(pods)
+	}
+
+	_, err = c.terminateVictims(pods, c.victimCount(len(pods)), true)
+	return err
+}
+
+// allowed returns true iff the given time falls into one of the allowed windows, if any.
+func (c *Chaoskube) allowed(now time.Time) bool {
+	if len(c.AllowedWindows) == 0 {
+		return true
+	}
+
+	for _, window := range c.AllowedWindows {
+		if window.Includes(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextRun returns when the next run is due according to the schedule, evaluated in the
+// configured timezone. It returns the zero time if there's no schedule.
+func (c *Chaoskube) NextRun() time.Time {
+	if c.Schedule == nil {
+		return time.Time{}
+	}
+	return c.Schedule.Next(c.Now().In(c.Timezone))
+}
+
+// terminateVictims picks and deletes up to count victims from the given list of pods and
+// returns the terminated ones. Victims that are protected by a PodDisruptionBudget, whose
+// workload is unhealthy or that are already gone are replaced by another candidate.
+// Each victim is reported to Datadog on its own if events is set.
+func (c *Chaoskube) terminateVictims(pods []v1.Pod, count int, events bool) ([]v1.Pod, error) {
+	attempts, blocked, skipped := 0, 0, 0
+	errs := []error{}
+	terminated := []v1.Pod{}
+	// pods terminated per workload in this run, which the workloads' status doesn't reflect yet
+	killed := map[Owner]int32{}
+
+	for attempts < count {
+		victims := c.pickVictims(pods, count-attempts)
+		if len(victims) == 0 {
+			break
+		}
+
+		for _, victim := range victims {
+			pods = c.withoutVictim(pods, victim)
+
+			// an earlier victim of this run may have started a cooldown
+			if reason := c.coolingDown(victim, c.Now()); reason != "" {
+				c.Logger.Debugf("Pod [%s/%s] is spared: %s", victim.Namespace, victim.Name, reason)
+				skipped++
+				continue
+			}
+
+			if c.isStale(victim) {
+				c.Logger.Debugf("Pod [%s/%s] is already gone", victim.Namespace, victim.Name)
+				skipped++
+				continue
+			}
+
+			if c.CheckWorkloadHealth {
+				if reason := c.unhealthyReason(victim, killed[c.Owner(victim)]); reason != "" {
+					c.Logger.WithFields(log.Fields{
+						"namespace":   victim.Namespace,
+						"name":        victim.Name,
+						"skip-reason": reason,
+					}).Info(msgWorkloadUnhealthy)
+					skipped++
+					continue
+				}
+			}
+
+			var err error
+			if events {
+				err = c.DeletePod(victim)
+			} else {
+				_, err = c.deletePod(victim)
+			}
+			if err == errPodProtected {
+				c.Logger.Debugf("Pod [%s/%s] is protected by a disruption budget", victim.Namespace, victim.Name)
+				blocked++
+				continue
+			}
+
+			attempts++
+			if err != nil {
+				c.Logger.Debugf("Failed to terminate pod [%s/%s]: %v", victim.Namespace, victim.Name, err)
+				errs = append(errs, err)
+				continue
+			}
+			terminated = append(terminated, victim)
+			killed[c.Owner(victim)]++
+		}
+	}
+
+	if blocked > 0 {
+		c.Stats.AddBlockedEvictions(blocked)
+		c.Logger.WithField("blocked", blocked).Info(msgEvictionBlocked)
+
+		if c.DDEvents && c.DDClient != nil {
+			if err := datadog.CountBlockedEvictions(c.DDClient, blocked); err != nil {
+				c.Logger.Debugf("Failed to report blocked evictions: %v", err)
+			}
+		}
+	}
+
+	if attempts == 0 && blocked == 0 && skipped == 0 {
+		c.Logger.Debug(msgVictimNotFound)
+	}
+
+	return terminated, utilerrors.NewAggregate(errs)
+}
+
+// withoutVictim returns the given list of pods without the victim.
+// When picking by owner, all pods sharing the victim's owner are removed as well.
+// Pods whose workload or node started cooling down during this run are removed, too.
+func (c *Chaoskube) withoutVictim(pods []v1.Pod, victim v1.Pod) []v1.Pod {
+	var owner Owner
+	if c.GroupByOwner {
+		owner = c.Owner(victim)
+	}
+
+	now := c.Now()
+	remaining := []v1.Pod{}
+
+	for _, pod := range pods {
+		if pod.Namespace == victim.Namespace && pod.Name == victim.Name {
+			continue
+		}
+		if c.GroupByOwner && c.Owner(pod) == owner {
+			continue
+		}
+		if c.coolingDown(pod, now) != "" {
+			continue
+		}
+		remaining = append(remaining, pod)
+	}
+
+	return remaining
 }
 
 // Victim returns a random pod from the list of Candidates.
@@ -136,60 +419,377 @@ func (c *Chaoskube) Victim() (v1.Pod, error) {
 
 	c.Logger.Debugf("Found [%d] candidates", len(pods))
 
-	if len(pods) == 0 {
+	victims := c.pickVictims(pods, 1)
+	if len(victims) == 0 {
 		return v1.Pod{}, errPodNotFound
 	}
 
-	index := rand.Intn(len(pods))
+	return victims[0], nil
+}
+
+// Victims returns a list of distinct random pods from the list of Candidates.
+// The number of pods is determined by the configured count, percentage and limit.
+// It returns an error if there are no candidates to choose from.
+func (c *Chaoskube) Victims() ([]v1.Pod, error) {
+	pods, err := c.Candidates()
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("Found [%d] candidates", len(pods))
+
+	victims := c.pickVictims(pods, c.victimCount(len(pods)))
+	if len(victims) == 0 {
+		return nil, errPodNotFound
+	}
+
+	return victims, nil
+}
+
+// pickVictims picks up to count distinct pods from the given list.
+func (c *Chaoskube) pickVictims(pods []v1.Pod, count int) []v1.Pod {
+	if c.GroupByOwner {
+		return c.pickVictimsByOwner(pods, count)
+	}
+
+	victims := []v1.Pod{}
+
+	// don't remove picks from the caller's list
+	pods = append([]v1.Pod(nil), pods...)
+
+	for len(victims) < count && len(pods) > 0 {
+		index, err := c.pick(pods)
+		if err != nil {
+			break
+		}
+
+		victims = append(victims, pods[index])
+		pods = append(pods[:index], pods[index+1:]...)
+	}
+
+	return victims
+}
+
+// victimCount returns the number of pods to terminate given the number of candidates.
+func (c *Chaoskube) victimCount(candidates int) int {
+	count := c.VictimCount
+	if c.VictimPercentage > 0 {
+		// round up so that any percentage of a non-empty list hits at least one pod
+		count = (candidates*c.VictimPercentage + 99) / 100
+	}
+
+	if c.MaxVictims > 0 && count > c.MaxVictims {
+		count = c.MaxVictims
+	}
+
+	return count
+}
 
-	return pods[index], nil
+// pick returns the index of a random pod in the given list.
+// Pods are weighted by their weight annotation if weighting is enabled.
+func (c *Chaoskube) pick(pods []v1.Pod) (int, error) {
+	if len(pods) == 0 {
+		return 0, errPodNotFound
+	}
+
+	if c.WeightAnnotation == "" {
+		return c.Rand.Intn(len(pods)), nil
+	}
+
+	total := 0
+	for _, pod := range pods {
+		weight := c.Weight(pod)
+		if total > maxInt-weight {
+			total = maxInt
+			break
+		}
+		total += weight
+	}
+
+	// all remaining pods have a weight of zero
+	if total <= 0 {
+		return 0, errPodNotFound
+	}
+
+	pick := c.Rand.Intn(total)
+	for i, pod := range pods {
+		pick -= c.Weight(pod)
+		if pick < 0 {
+			return i, nil
+		}
+	}
+
+	return 0, errPodNotFound
+}
+
+// Weight returns the weight of the given pod as defined by its weight annotation.
+// Pods without the annotation or with an invalid value get the configured default weight.
+// Weights are capped at MaxWeight and a negative default weight counts as zero.
+func (c *Chaoskube) Weight(pod v1.Pod) int {
+	value, ok := pod.Annotations[c.WeightAnnotation]
+	if !ok {
+		return clampWeight(c.DefaultWeight)
+	}
+
+	weight, err := strconv.Atoi(value)
+	if err != nil || weight < 0 {
+		c.Logger.Debugf("Invalid weight [%s] on pod [%s/%s], using default", value, pod.Namespace, pod.Name)
+		return clampWeight(c.DefaultWeight)
+	}
+
+	return clampWeight(weight)
+}
+
+// clampWeight limits the given weight to the range from zero to MaxWeight.
+func clampWeight(weight int) int {
+	if weight < 0 {
+		return 0
+	}
+	if weight > MaxWeight {
+		return MaxWeight
+	}
+	return weight
 }
 
 // Candidates returns the list of pods that are available for termination.
-// It returns all pods that match the configured label, annotation and namespace selectors.
+// It returns all pods that match the configured label, annotation and namespace selectors
+// as well as the configured phases, readiness, minimum age and minimum replicas.
+// Pods that are already being deleted or whose workload or node is cooling down are ignored.
 func (c *Chaoskube) Candidates() ([]v1.Pod, error) {
-	listOptions := metav1.ListOptions{LabelSelector: c.Labels.String()}
+	// start each run with a fresh view of the cluster's workloads, nodes and namespaces
+	c.workloads = newWorkloadCache(c.Client)
+	c.nodes = newNodeCache(c.Client)
+	c.namespaces = newNamespaceCache(c.Client)
 
-	podList, err := c.Client.CoreV1().Pods(v1.NamespaceAll).List(listOptions)
+	pods, err := c.listPods()
 	if err != nil {
 		return nil, err
 	}
 
-	pods, err := filterByNamespaces(podList.Items, c.Namespaces)
+	pods, err = filterByNamespaces(pods, c.Namespaces)
 	if err != nil {
 		return nil, err
 	}
 
+	// cheap filters on the pods themselves go first, so that fewer pods need API calls below
+	pods = filterByNamePatterns(pods, c.NamespacePatterns, c.PodNamePatterns)
+
 	pods, err = filterByAnnotations(pods, c.Annotations)
 	if err != nil {
 		return nil, err
 	}
 
+	pods = filterByStatus(pods, c.Phases, c.IncludeUnready)
+
+	pods = filterByMinimumAge(pods, c.MinimumAge, c.Now())
+
+	pods, err = c.filterByNamespaceLabels(pods)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err = c.filterByOptOut(pods)
+	if err != nil {
+		return nil, err
+	}
+
+	pods = c.filterBySelf(pods)
+
+	pods = c.filterByNode(pods)
+
+	pods = c.filterByCategories(pods)
+
+	pods = c.filterByReplicas(pods)
+
+	pods = c.filterByCooldown(pods)
+
 	return pods, nil
 }
 
 // DeletePod deletes the given pod.
 // It will not delete the pod if dry-run mode is enabled.
 func (c *Chaoskube) DeletePod(victim v1.Pod) error {
+	owner, err := c.deletePod(victim)
+
+	//send ddEvent
+	if err == nil && !c.DryRun && c.DDEvents {
+		err := datadog.NewEvent(c.DDClient, victim, owner)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	return err
+}
+
+// deletePod deletes the given pod like DeletePod without reporting it to Datadog.
+// It returns the pod's owner if grouping by owner.
+func (c *Chaoskube) deletePod(victim v1.Pod) (string, error) {
 	// add custom logger for deteted pot in order to aggregate data in kibana
-	logger.WithCustomFields(victim.Name).Info("terminating pod")
+	entry := logger.WithCustomFields(c.Logger, victim.Name).WithFields(log.Fields{
+		"namespace": victim.Namespace,
+		"name":      victim.Name,
+	})
+	if c.WeightAnnotation != "" {
+		entry = entry.WithField("weight", c.Weight(victim))
+	}
+
+	owner := ""
+	if c.GroupByOwner {
+		o := c.Owner(victim)
+		owner = o.String()
+		entry = entry.WithFields(log.Fields{"owner-kind": o.Kind, "owner-name": o.Name})
+	}
+
+	mode := c.terminationModeFor(victim)
+	entry.WithField("termination-mode", mode.String()).Info("terminating pod")
 
 	if c.DryRun {
-		return nil
+		c.coolDown(victim)
+		return owner, nil
 	}
 
-	err := c.Client.CoreV1().Pods(victim.Namespace).Delete(victim.Name, nil)
+	var err error
+	if c.UseEviction {
+		err = c.evictPod(victim, mode)
+	} else {
+		err = c.Client.CoreV1().Pods(victim.Namespace).Delete(victim.Name, mode.DeleteOptions())
+	}
 
 	if err == nil {
-		//send ddEvent
-		if c.DDEvents {
-			err := datadog.NewEvent(c.DDClient, victim)
-			if err != nil {
-				log.Fatal(err)
+		c.coolDown(victim)
+	}
+	return owner, err
+}
+
+// evictPod evicts the given pod through the Eviction API which respects PodDisruptionBudgets.
+// It returns errPodProtected if a disruption budget doesn't allow the eviction.
+func (c *Chaoskube) evictPod(victim v1.Pod, mode TerminationMode) error {
+	eviction := &policy.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: victim.Namespace,
+			Name:      victim.Name,
+		},
+		DeleteOptions: mode.DeleteOptions(),
+	}
+
+	err := c.Client.PolicyV1beta1().Evictions(victim.Namespace).Evict(eviction)
+	if apierrors.IsTooManyRequests(err) {
+		return errPodProtected
+	}
+
+	return err
+}
+
+// includedNamespaces returns the namespaces explicitly included by the namespace selector, e.g.
+// "foo" and "bar" for "foo,bar,!baz". It returns nothing if the selector doesn't include any
+// namespaces, in which case pods of all namespaces are candidates.
+func includedNamespaces(namespaces labels.Selector) []string {
+	reqs, _ := namespaces.Requirements()
+
+	included := []string{}
+	for i, req := range reqs {
+		// requirements are sorted, skip repeated namespaces
+		if req.Operator() == selection.Exists && (i == 0 || reqs[i-1].Key() != req.Key()) {
+			included = append(included, req.Key())
+		}
+	}
+
+	return included
+}
+
+// filterByNamespaceLabels filters a list of pods by the labels of their Namespace objects.
+func (c *Chaoskube) filterByNamespaceLabels(pods []v1.Pod) ([]v1.Pod, error) {
+	// empty filter returns original list
+	if isEmpty(c.NamespaceLabels) {
+		return pods, nil
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: c.NamespaceLabels.String()}
+
+	namespaceList, err := c.Client.CoreV1().Namespaces().List(listOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := map[string]bool{}
+	for _, namespace := range namespaceList.Items {
+		namespaces[namespace.Name] = true
+	}
+
+	filteredList := []v1.Pod{}
+
+	for _, pod := range pods {
+		if namespaces[pod.Namespace] {
+			filteredList = append(filteredList, pod)
+		}
+	}
+
+	return filteredList, nil
+}
+
+// filterByNamePatterns filters a list of pods by patterns on their namespace's and their own name.
+func filterByNamePatterns(pods []v1.Pod, namespacePatterns, podNamePatterns []util.NamePattern) []v1.Pod {
+	// empty filter returns original list
+	if len(namespacePatterns) == 0 && len(podNamePatterns) == 0 {
+		return pods
+	}
+
+	filteredList := []v1.Pod{}
+
+	for _, pod := range pods {
+		if util.MatchesNamePatterns(namespacePatterns, pod.Namespace) && util.MatchesNamePatterns(podNamePatterns, pod.Name) {
+			filteredList = append(filteredList, pod)
+		}
+	}
+
+	return filteredList
+}
+
+// filterByCategories excludes pods owned by one of the excluded controller kinds or having one of
+// the excluded priority or QoS classes.
+func (c *Chaoskube) filterByCategories(pods []v1.Pod) []v1.Pod {
+	// empty filter returns original list
+	if len(c.ExcludedOwnerKinds) == 0 && len(c.ExcludedPriorityClasses) == 0 && len(c.ExcludedQOSClasses) == 0 {
+		return pods
+	}
+
+	filteredList := []v1.Pod{}
+
+	for _, pod := range pods {
+		if reason := excludedCategory(pod, c.ExcludedOwnerKinds, c.ExcludedPriorityClasses, c.ExcludedQOSClasses); reason != "" {
+			c.Logger.Debugf("Excluding pod [%s/%s]: %s", pod.Namespace, pod.Name, reason)
+			continue
+		}
+
+		filteredList = append(filteredList, pod)
+	}
+
+	return filteredList
+}
+
+// excludedCategory returns why the given pod belongs to an excluded category, or nothing if it doesn't.
+func excludedCategory(pod v1.Pod, ownerKinds, priorityClasses []string, qosClasses []v1.PodQOSClass) string {
+	if controller := metav1.GetControllerOf(&pod); controller != nil {
+		for _, kind := range ownerKinds {
+			if strings.EqualFold(kind, controller.Kind) {
+				return fmt.Sprintf("owned by excluded kind %s", controller.Kind)
 			}
 		}
 	}
-	return err
+
+	for _, priorityClass := range priorityClasses {
+		if pod.Spec.PriorityClassName == priorityClass {
+			return fmt.Sprintf("excluded priority class %s", priorityClass)
+		}
+	}
+
+	for _, qosClass := range qosClasses {
+		if pod.Status.QOSClass == qosClass {
+			return fmt.Sprintf("excluded QoS class %s", qosClass)
+		}
+	}
+
+	return ""
 }
 
 // filterByNamespaces filters a list of pods by a given namespace selector.
@@ -269,3 +869,84 @@ func filterByAnnotations(pods []v1.Pod, annotations labels.Selector) ([]v1.Pod,
 
 	return filteredList, nil
 }
+
+// filterByStatus filters a list of pods by their phase and readiness.
+// Pods that are already being deleted are always filtered out.
+func filterByStatus(pods []v1.Pod, phases []v1.PodPhase, includeUnready bool) []v1.Pod {
+	filteredList := []v1.Pod{}
+
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		if len(phases) > 0 && !hasPhase(pod, phases) {
+			continue
+		}
+
+		if !includeUnready && !isReady(pod) {
+			continue
+		}
+
+		filteredList = append(filteredList, pod)
+	}
+
+	return filteredList
+}
+
+// hasPhase returns true iff the given pod is in one of the given phases.
+func hasPhase(pod v1.Pod, phases []v1.PodPhase) bool {
+	for _, phase := range phases {
+		if pod.Status.Phase == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// isReady returns true iff the given pod's Ready condition is true.
+func isReady(pod v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// filterByMinimumAge filters out pods that have been running for less than a given duration.
+// A valid minimum age annotation on a pod overrides the given duration.
+func filterByMinimumAge(pods []v1.Pod, minimumAge time.Duration, now time.Time) []v1.Pod {
+	filteredList := []v1.Pod{}
+
+	for _, pod := range pods {
+		podMinimumAge := minimumAge
+		if value, ok := pod.Annotations[MinimumAgeAnnotation]; ok {
+			if age, err := time.ParseDuration(value); err == nil {
+				podMinimumAge = age
+			}
+		}
+
+		// no minimum age required, include pod regardless of its start time
+		if podMinimumAge <= 0 {
+			filteredList = append(filteredList, pod)
+			continue
+		}
+
+		// pods that haven't started yet are too young by definition
+		if pod.Status.StartTime == nil {
+			continue
+		}
+
+		if now.Sub(pod.Status.StartTime.Time) >= podMinimumAge {
+			filteredList = append(filteredList, pod)
+		}
+	}
+
+	return filteredList
+}
+
+// isEmpty returns true iff the selector is unset or selects everything.
+func isEmpty(selector labels.Selector) bool {
+	return selector == nil || selector.Empty()
+}
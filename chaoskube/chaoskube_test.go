@@ -1,18 +1,30 @@
 package chaoskube
 
 import (
+	"errors"
+	"fmt"
 	"math/rand"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
 
-	"github.com/linki/chaoskube/util"
+	"github.com/metrosystems-cpe/chaoskube/util"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -22,11 +34,11 @@ type Suite struct {
 }
 
 var (
-	logger, logOutput = test.NewNullLogger()
+	nullLogger, logOutput = test.NewNullLogger()
 )
 
 func (suite *Suite) SetupTest() {
-	logger.SetLevel(log.DebugLevel)
+	nullLogger.SetLevel(log.DebugLevel)
 	logOutput.Reset()
 }
 
@@ -51,8 +63,10 @@ func (suite *Suite) TestNew() {
 		excludedTimesOfDay,
 		excludedDaysOfYear,
 		time.UTC,
-		logger,
+		nullLogger,
 		false,
+		false,
+		nil,
 	)
 	suite.Require().NotNil(chaoskube)
 
@@ -64,7 +78,7 @@ func (suite *Suite) TestNew() {
 	suite.Equal(excludedTimesOfDay, chaoskube.ExcludedTimesOfDay)
 	suite.Equal(excludedDaysOfYear, chaoskube.ExcludedDaysOfYear)
 	suite.Equal(time.UTC, chaoskube.Timezone)
-	suite.Equal(logger, chaoskube.Logger)
+	suite.Equal(nullLogger, chaoskube.Logger)
 	suite.Equal(false, chaoskube.DryRun)
 }
 
@@ -114,6 +128,76 @@ func (suite *Suite) TestCandidates() {
 	}
 }
 
+func (suite *Suite) TestFilterByStatus() {
+	now := metav1.NewTime(ThankGodItsFriday{}.Now())
+
+	running := util.NewPod("default", "running")
+
+	unready := util.NewPod("default", "unready")
+	unready.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionFalse}}
+
+	pending := util.NewPod("default", "pending")
+	pending.Status = v1.PodStatus{Phase: v1.PodPending}
+
+	succeeded := util.NewPod("default", "succeeded")
+	succeeded.Status.Phase = v1.PodSucceeded
+
+	terminating := util.NewPod("default", "terminating")
+	terminating.DeletionTimestamp = &now
+
+	pods := []v1.Pod{running, unready, pending, succeeded, terminating}
+
+	for _, tt := range []struct {
+		phases         []v1.PodPhase
+		includeUnready bool
+		expected       []string
+	}{
+		{[]v1.PodPhase{v1.PodRunning}, false, []string{"running"}},
+		{[]v1.PodPhase{v1.PodRunning}, true, []string{"running", "unready"}},
+		{[]v1.PodPhase{v1.PodRunning, v1.PodPending}, true, []string{"running", "unready", "pending"}},
+		{[]v1.PodPhase{}, false, []string{"running", "succeeded"}},
+		{[]v1.PodPhase{}, true, []string{"running", "unready", "pending", "succeeded"}},
+	} {
+		names := []string{}
+		for _, pod := range filterByStatus(pods, tt.phases, tt.includeUnready) {
+			names = append(names, pod.Name)
+		}
+
+		suite.Equal(tt.expected, names)
+	}
+}
+
+func (suite *Suite) TestFilterByMinimumAge() {
+	now := ThankGodItsFriday{}.Now()
+
+	for _, tt := range []struct {
+		minimumAge time.Duration
+		startTime  *time.Time
+		annotation string
+		expected   bool
+	}{
+		{0, nil, "", true},
+		{time.Hour, nil, "", false},
+		{time.Hour, timePtr(now.Add(-2 * time.Hour)), "", true},
+		{time.Hour, timePtr(now.Add(-30 * time.Minute)), "", false},
+		{time.Hour, timePtr(now.Add(-30 * time.Minute)), "10m", true},
+		{0, timePtr(now.Add(-30 * time.Minute)), "1h", false},
+		{time.Hour, timePtr(now.Add(-30 * time.Minute)), "invalid", false},
+	} {
+		pod := util.NewPod("default", "foo")
+		if tt.startTime != nil {
+			startTime := metav1.NewTime(*tt.startTime)
+			pod.Status.StartTime = &startTime
+		}
+		if tt.annotation != "" {
+			pod.Annotations[MinimumAgeAnnotation] = tt.annotation
+		}
+
+		pods := filterByMinimumAge([]v1.Pod{pod}, tt.minimumAge, now)
+		suite.Equal(tt.expected, len(pods) == 1)
+	}
+}
+
 func (suite *Suite) TestVictim() {
 	foo := map[string]string{"namespace": "default", "name": "foo"}
 	bar := map[string]string{"namespace": "testing", "name": "bar"}
@@ -127,8 +211,6 @@ func (suite *Suite) TestVictim() {
 		{4000, "", bar},
 		{4000, "app=foo", foo},
 	} {
-		rand.Seed(tt.seed)
-
 		labelSelector, err := labels.Parse(tt.labelSelector)
 		suite.Require().NoError(err)
 
@@ -142,11 +224,902 @@ func (suite *Suite) TestVictim() {
 			time.UTC,
 			false,
 		)
+		chaoskube.Rand = rand.New(rand.NewSource(tt.seed))
+
+		suite.assertVictim(chaoskube, tt.victim)
+	}
+}
+
+// TestVictimsReproducible tests that the same seed picks the same sequence of victims
+func (suite *Suite) TestVictimsReproducible() {
+	picks := func(seed int64) []string {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		chaoskube.Rand = rand.New(rand.NewSource(seed))
+		chaoskube.VictimCount = 3
+
+		for i := 0; i < 10; i++ {
+			pod := util.NewPod("default", fmt.Sprintf("pod-%d", i))
+			_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pod)
+			suite.Require().NoError(err)
+		}
+
+		names := []string{}
+		for i := 0; i < 5; i++ {
+			victims, err := chaoskube.Victims()
+			suite.Require().NoError(err)
+
+			for _, victim := range victims {
+				names = append(names, victim.Name)
+			}
+		}
+		return names
+	}
+
+	suite.Equal(picks(42), picks(42))
+	suite.NotEqual(picks(42), picks(43))
+}
+
+// TestWeightedVictim tests that victims are picked according to their weight annotation
+func (suite *Suite) TestWeightedVictim() {
+	foo := map[string]string{"namespace": "default", "name": "foo"}
+	bar := map[string]string{"namespace": "testing", "name": "bar"}
+
+	for _, tt := range []struct {
+		defaultWeight int
+		weights       map[string]string
+		victim        map[string]string
+	}{
+		{0, map[string]string{"foo": "5"}, foo},
+		{0, map[string]string{"bar": "1"}, bar},
+		{1, map[string]string{"foo": "0"}, bar},
+		{0, map[string]string{"foo": "0", "bar": "3"}, bar},
+	} {
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		chaoskube.WeightAnnotation = "chaos.weight"
+		chaoskube.DefaultWeight = tt.defaultWeight
+
+		suite.annotatePods(chaoskube, "chaos.weight", tt.weights)
 
 		suite.assertVictim(chaoskube, tt.victim)
 	}
 }
 
+// TestWeightedVictimWithoutWeights tests that no victim is found when all weights are zero
+func (suite *Suite) TestWeightedVictimWithoutWeights() {
+	chaoskube := suite.setupWithPods(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+	chaoskube.WeightAnnotation = "chaos.weight"
+	chaoskube.DefaultWeight = 0
+
+	_, err := chaoskube.Victim()
+	suite.Equal(errPodNotFound, err)
+}
+
+// TestWeightedVictimNegativeDefaultWeight tests that a negative default weight counts as zero
+func (suite *Suite) TestWeightedVictimNegativeDefaultWeight() {
+	chaoskube := suite.setupWithPods(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+	chaoskube.WeightAnnotation = "chaos.weight"
+	chaoskube.DefaultWeight = -5
+
+	_, err := chaoskube.Victim()
+	suite.Equal(errPodNotFound, err)
+
+	suite.annotatePods(chaoskube, "chaos.weight", map[string]string{"bar": "1"})
+
+	suite.assertVictim(chaoskube, map[string]string{"namespace": "testing", "name": "bar"})
+}
+
+// TestWeightedVictimOverflowingWeights tests that huge weights neither overflow nor panic
+func (suite *Suite) TestWeightedVictimOverflowingWeights() {
+	chaoskube := suite.setupWithPods(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+	chaoskube.WeightAnnotation = "chaos.weight"
+	chaoskube.DefaultWeight = 0
+
+	huge := strconv.Itoa(maxInt)
+	suite.annotatePods(chaoskube, "chaos.weight", map[string]string{"foo": huge, "bar": huge})
+
+	for i := 0; i < 10; i++ {
+		_, err := chaoskube.Victim()
+		suite.Require().NoError(err)
+	}
+}
+
+// TestWeight tests that a pod's weight is read from its annotation, falling back to the default weight
+func (suite *Suite) TestWeight() {
+	chaoskube := suite.setup(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+	chaoskube.WeightAnnotation = "chaos.weight"
+	chaoskube.DefaultWeight = 2
+
+	for _, tt := range []struct {
+		annotations map[string]string
+		expected    int
+	}{
+		{map[string]string{}, 2},
+		{map[string]string{"chaos.weight": "5"}, 5},
+		{map[string]string{"chaos.weight": "0"}, 0},
+		{map[string]string{"chaos.weight": "-1"}, 2},
+		{map[string]string{"chaos.weight": "lots"}, 2},
+		{map[string]string{"chaos.weight": "9999999999"}, MaxWeight},
+	} {
+		pod := util.NewPod("default", "foo")
+		pod.Annotations = tt.annotations
+
+		suite.Equal(tt.expected, chaoskube.Weight(pod))
+	}
+}
+
+// TestVictims tests that the configured number of distinct victims is returned
+func (suite *Suite) TestVictims() {
+	for _, tt := range []struct {
+		count      int
+		percentage int
+		max        int
+		expected   int
+	}{
+		{1, 0, 0, 1},
+		{2, 0, 0, 2},
+		{3, 0, 0, 2},
+		{2, 0, 1, 1},
+		{1, 50, 0, 1},
+		{1, 100, 0, 2},
+		{1, 10, 0, 1},
+		{1, 100, 1, 1},
+	} {
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		chaoskube.VictimCount = tt.count
+		chaoskube.VictimPercentage = tt.percentage
+		chaoskube.MaxVictims = tt.max
+
+		victims, err := chaoskube.Victims()
+		suite.Require().NoError(err)
+		suite.Len(victims, tt.expected)
+
+		seen := map[string]bool{}
+		for _, victim := range victims {
+			suite.False(seen[victim.Name])
+			seen[victim.Name] = true
+		}
+	}
+}
+
+func (suite *Suite) TestOwner() {
+	chaoskube := suite.setup(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+	suite.createReplicaSet(chaoskube, "default", "foo-1234", "foo")
+	suite.createReplicaSet(chaoskube, "default", "bar-1234", "")
+
+	for _, tt := range []struct {
+		kind     string
+		name     string
+		expected Owner
+	}{
+		{"", "", Owner{Kind: "Pod", Namespace: "default", Name: "pod"}},
+		{"ReplicaSet", "foo-1234", Owner{Kind: "Deployment", Namespace: "default", Name: "foo"}},
+		{"ReplicaSet", "bar-1234", Owner{Kind: "ReplicaSet", Namespace: "default", Name: "bar-1234"}},
+		{"ReplicaSet", "missing", Owner{Kind: "ReplicaSet", Namespace: "default", Name: "missing"}},
+		{"StatefulSet", "baz", Owner{Kind: "StatefulSet", Namespace: "default", Name: "baz"}},
+	} {
+		pod := util.NewPod("default", "pod")
+		if tt.kind != "" {
+			setOwner(&pod, tt.kind, tt.name)
+		}
+
+		suite.Equal(tt.expected, chaoskube.Owner(pod))
+	}
+}
+
+// TestCandidatesFromPodCache tests that candidates are listed from the pod cache
+func (suite *Suite) TestCandidatesFromPodCache() {
+	foo := map[string]string{"namespace": "default", "name": "foo"}
+	bar := map[string]string{"namespace": "testing", "name": "bar"}
+
+	for _, tt := range []struct {
+		namespaceSelector string
+		pods              []map[string]string
+	}{
+		{"", []map[string]string{foo, bar}},
+		{"default", []map[string]string{foo}},
+		{"default,testing", []map[string]string{foo, bar}},
+	} {
+		namespaceSelector, err := labels.Parse(tt.namespaceSelector)
+		suite.Require().NoError(err)
+
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			namespaceSelector,
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+
+		stopCh := make(chan struct{})
+
+		suite.Require().NoError(chaoskube.UsePodCache(0, stopCh))
+
+		suite.assertCandidates(chaoskube, tt.pods)
+
+		close(stopCh)
+	}
+}
+
+// TestCandidatesNamespaceScoped tests that pods are only listed in the included namespaces if any
+func (suite *Suite) TestCandidatesNamespaceScoped() {
+	for _, tt := range []struct {
+		namespaceSelector string
+		listed            []string
+		candidates        []string
+	}{
+		{"", []string{""}, []string{"foo", "bar"}},
+		{"!testing", []string{""}, []string{"foo"}},
+		{"default", []string{"default"}, []string{"foo"}},
+		{"default,testing,!testing", []string{"default", "testing"}, []string{"foo"}},
+	} {
+		namespaceSelector, err := labels.Parse(tt.namespaceSelector)
+		suite.Require().NoError(err)
+
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			namespaceSelector,
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+
+		listed := []string{}
+		chaoskube.Client.(*fake.Clientset).PrependReactor("list", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+			listed = append(listed, action.GetNamespace())
+			return false, nil, nil
+		})
+
+		pods, err := chaoskube.Candidates()
+		suite.Require().NoError(err)
+
+		names := []string{}
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		suite.ElementsMatch(tt.candidates, names)
+		suite.ElementsMatch(tt.listed, listed)
+	}
+}
+
+// TestCandidatesNamespaceLabels tests that pods can be filtered by the labels of their namespace
+func (suite *Suite) TestCandidatesNamespaceLabels() {
+	foo := map[string]string{"namespace": "default", "name": "foo"}
+	bar := map[string]string{"namespace": "testing", "name": "bar"}
+
+	for _, tt := range []struct {
+		namespaceLabels string
+		pods            []map[string]string
+	}{
+		{"", []map[string]string{foo, bar}},
+		{"team=payments", []map[string]string{foo, bar}},
+		{"team=payments,chaos=enabled", []map[string]string{foo}},
+		{"chaos!=enabled", []map[string]string{bar}},
+		{"team=checkout", []map[string]string{}},
+	} {
+		namespaceLabels, err := labels.Parse(tt.namespaceLabels)
+		suite.Require().NoError(err)
+
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		chaoskube.NamespaceLabels = namespaceLabels
+
+		for name, namespaceLabels := range map[string]map[string]string{
+			"default": {"team": "payments", "chaos": "enabled"},
+			"testing": {"team": "payments"},
+		} {
+			namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: namespaceLabels}}
+			_, err := chaoskube.Client.CoreV1().Namespaces().Create(namespace)
+			suite.Require().NoError(err)
+		}
+
+		suite.assertCandidates(chaoskube, tt.pods)
+	}
+}
+
+// TestCandidatesNamePatterns tests that pods can be filtered by patterns on namespace and pod names
+func (suite *Suite) TestCandidatesNamePatterns() {
+	foo := map[string]string{"namespace": "default", "name": "foo"}
+	bar := map[string]string{"namespace": "testing", "name": "bar"}
+
+	for _, tt := range []struct {
+		namespacePatterns string
+		podNamePatterns   string
+		pods              []map[string]string
+	}{
+		{"", "", []map[string]string{foo, bar}},
+		{"test*", "", []map[string]string{bar}},
+		{"!^def.*", "", []map[string]string{bar}},
+		{"", "^f", []map[string]string{foo}},
+		{"*", "!ba?", []map[string]string{foo}},
+		{"testing", "foo", []map[string]string{}},
+	} {
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+
+		var err error
+		chaoskube.NamespacePatterns, err = util.ParseNamePatterns(tt.namespacePatterns)
+		suite.Require().NoError(err)
+		chaoskube.PodNamePatterns, err = util.ParseNamePatterns(tt.podNamePatterns)
+		suite.Require().NoError(err)
+
+		suite.assertCandidates(chaoskube, tt.pods)
+	}
+}
+
+// TestCandidatesNodeFilter tests that pods can be filtered by the labels and taints of their node
+func (suite *Suite) TestCandidatesNodeFilter() {
+	for _, tt := range []struct {
+		nodeLabels string
+		nodeTaints string
+		expected   []string
+	}{
+		{"", "", []string{"spot-1", "master-1", "unscheduled"}},
+		{"pool=spot", "", []string{"spot-1"}},
+		{"", "!node-role.kubernetes.io/master", []string{"spot-1"}},
+		{"", "node-role.kubernetes.io/master", []string{"master-1"}},
+		{"pool=spot", "node-role.kubernetes.io/master", []string{}},
+		{"pool", "", []string{"spot-1", "master-1"}},
+	} {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+
+		var err error
+		chaoskube.NodeLabels, err = labels.Parse(tt.nodeLabels)
+		suite.Require().NoError(err)
+		chaoskube.NodeTaints, err = labels.Parse(tt.nodeTaints)
+		suite.Require().NoError(err)
+
+		nodes := []v1.Node{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "spot", Labels: map[string]string{"pool": "spot"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "master", Labels: map[string]string{"pool": "master"}},
+				Spec: v1.NodeSpec{Taints: []v1.Taint{
+					{Key: "node-role.kubernetes.io/master", Effect: v1.TaintEffectNoSchedule},
+				}},
+			},
+		}
+		for i := range nodes {
+			_, err := chaoskube.Client.CoreV1().Nodes().Create(&nodes[i])
+			suite.Require().NoError(err)
+		}
+
+		for name, nodeName := range map[string]string{"spot-1": "spot", "master-1": "master", "unscheduled": ""} {
+			pod := util.NewPod("default", name)
+			pod.Spec.NodeName = nodeName
+			_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pod)
+			suite.Require().NoError(err)
+		}
+
+		listed := 0
+		chaoskube.Client.(*fake.Clientset).PrependReactor("list", "nodes", func(action ktesting.Action) (bool, runtime.Object, error) {
+			listed++
+			return false, nil, nil
+		})
+
+		pods, err := chaoskube.Candidates()
+		suite.Require().NoError(err)
+
+		names := []string{}
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		suite.ElementsMatch(tt.expected, names)
+
+		// nodes are listed at most once per run
+		suite.True(listed <= 1)
+	}
+}
+
+// TestCandidatesExcludedCategories tests that pods can be excluded by owner kind, priority class and QoS class
+func (suite *Suite) TestCandidatesExcludedCategories() {
+	for _, tt := range []struct {
+		ownerKinds      []string
+		priorityClasses []string
+		qosClasses      []v1.PodQOSClass
+		expected        []string
+		reason          string
+	}{
+		{nil, nil, nil, []string{"daemon", "job", "critical", "guaranteed"}, ""},
+		{[]string{"daemonset", "Job"}, nil, nil, []string{"critical", "guaranteed"}, "owned by excluded kind DaemonSet"},
+		{nil, []string{"system-cluster-critical"}, nil, []string{"daemon", "job", "guaranteed"}, "excluded priority class system-cluster-critical"},
+		{nil, nil, []v1.PodQOSClass{v1.PodQOSGuaranteed}, []string{"daemon", "job", "critical"}, "excluded QoS class Guaranteed"},
+	} {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		chaoskube.ExcludedOwnerKinds = tt.ownerKinds
+		chaoskube.ExcludedPriorityClasses = tt.priorityClasses
+		chaoskube.ExcludedQOSClasses = tt.qosClasses
+
+		pods := []v1.Pod{
+			util.NewPod("default", "daemon"),
+			util.NewPod("default", "job"),
+			util.NewPod("default", "critical"),
+			util.NewPod("default", "guaranteed"),
+		}
+		setOwner(&pods[0], "DaemonSet", "daemon")
+		setOwner(&pods[1], "Job", "job")
+		pods[2].Spec.PriorityClassName = "system-cluster-critical"
+		pods[3].Status.QOSClass = v1.PodQOSGuaranteed
+
+		for i := range pods {
+			_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pods[i])
+			suite.Require().NoError(err)
+		}
+
+		candidates, err := chaoskube.Candidates()
+		suite.Require().NoError(err)
+
+		names := []string{}
+		for _, pod := range candidates {
+			names = append(names, pod.Name)
+		}
+		suite.ElementsMatch(tt.expected, names)
+
+		if tt.reason != "" {
+			found := false
+			for _, entry := range logOutput.AllEntries() {
+				if entry.Level == log.DebugLevel && strings.HasSuffix(entry.Message, ": "+tt.reason) {
+					found = true
+				}
+			}
+			suite.True(found, tt.reason)
+		}
+	}
+}
+
+// TestCandidatesOptOut tests that pods opted out on their own or through their namespace are never candidates
+func (suite *Suite) TestCandidatesOptOut() {
+	now := ThankGodItsFriday{}.Now()
+	later := now.Add(time.Hour).Format(time.RFC3339)
+	earlier := now.Add(-time.Hour).Format(time.RFC3339)
+
+	for _, tt := range []struct {
+		podAnnotations       map[string]string
+		namespaceLabels      map[string]string
+		namespaceAnnotations map[string]string
+		expected             []string
+	}{
+		{nil, nil, nil, []string{"foo", "bar"}},
+		{map[string]string{"chaos.disabled": "true"}, nil, nil, []string{"bar"}},
+		{map[string]string{"chaos.disabled": "false"}, nil, nil, []string{"foo", "bar"}},
+		{map[string]string{"chaos.disabled": "true", "chaos.disabled-until": later}, nil, nil, []string{"bar"}},
+		{map[string]string{"chaos.disabled": "true", "chaos.disabled-until": earlier}, nil, nil, []string{"foo", "bar"}},
+		{map[string]string{"chaos.disabled": "true", "chaos.disabled-until": "tomorrow"}, nil, nil, []string{"bar"}},
+		{nil, map[string]string{"chaos.disabled": "true"}, nil, []string{}},
+		{nil, nil, map[string]string{"chaos.disabled": "true"}, []string{}},
+		{nil, map[string]string{"chaos.disabled": "true"}, map[string]string{"chaos.disabled-until": later}, []string{}},
+		{nil, map[string]string{"chaos.disabled": "true"}, map[string]string{"chaos.disabled-until": earlier}, []string{"foo", "bar"}},
+	} {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		chaoskube.Now = ThankGodItsFriday{}.Now
+
+		namespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Labels:      tt.namespaceLabels,
+			Annotations: tt.namespaceAnnotations,
+		}}
+		_, err := chaoskube.Client.CoreV1().Namespaces().Create(namespace)
+		suite.Require().NoError(err)
+
+		pods := []v1.Pod{util.NewPod("default", "foo"), util.NewPod("default", "bar")}
+		pods[0].Annotations = tt.podAnnotations
+
+		for i := range pods {
+			_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pods[i])
+			suite.Require().NoError(err)
+		}
+
+		candidates, err := chaoskube.Candidates()
+		suite.Require().NoError(err)
+
+		names := []string{}
+		for _, pod := range candidates {
+			names = append(names, pod.Name)
+		}
+		suite.ElementsMatch(tt.expected, names)
+	}
+}
+
+// TestCandidatesOptOutNamespaceError tests that no pods are candidates if their namespace can't be checked
+func (suite *Suite) TestCandidatesOptOutNamespaceError() {
+	chaoskube := suite.setupWithPods(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+
+	chaoskube.Client.(*fake.Clientset).PrependReactor("list", "namespaces", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewInternalError(errors.New("unavailable"))
+	})
+
+	_, err := chaoskube.Candidates()
+	suite.Error(err)
+}
+
+// TestCandidatesOptOutNamespaceForbidden tests that no pods are candidates without permission to list namespaces
+func (suite *Suite) TestCandidatesOptOutNamespaceForbidden() {
+	chaoskube := suite.setupWithPods(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+
+	chaoskube.Client.(*fake.Clientset).PrependReactor("list", "namespaces", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(v1.Resource("namespaces"), "", errors.New("denied"))
+	})
+
+	pods, err := chaoskube.Candidates()
+	suite.Require().NoError(err)
+	suite.Empty(pods)
+
+	warnings := 0
+	for _, entry := range logOutput.AllEntries() {
+		if entry.Level == log.WarnLevel && strings.HasPrefix(entry.Message, "Not allowed to list namespaces") {
+			warnings++
+		}
+	}
+	suite.Equal(1, warnings)
+}
+
+// TestCandidatesExcludeSelf tests that chaoskube's own pod and its peers are never candidates
+func (suite *Suite) TestCandidatesExcludeSelf() {
+	for _, tt := range []struct {
+		selfName string
+		expected []string
+		warning  bool
+	}{
+		// own pod unknown
+		{"", []string{"chaoskube-old-a", "chaoskube-new-a", "chaoskube-new-b", "other-a", "bare"}, false},
+		// own pod and all pods of its Deployment, even across ReplicaSets
+		{"chaoskube-new-a", []string{"other-a", "bare"}, false},
+		// a bare pod only protects itself
+		{"bare", []string{"chaoskube-old-a", "chaoskube-new-a", "chaoskube-new-b", "other-a"}, false},
+		// own pod is gone, which is worth a warning
+		{"gone", []string{"chaoskube-old-a", "chaoskube-new-a", "chaoskube-new-b", "other-a", "bare"}, true},
+	} {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		if tt.selfName != "" {
+			chaoskube.SelfNamespace = "default"
+			chaoskube.SelfName = tt.selfName
+		}
+
+		suite.createReplicaSet(chaoskube, "default", "chaoskube-old", "chaoskube")
+		suite.createReplicaSet(chaoskube, "default", "chaoskube-new", "chaoskube")
+		suite.createReplicaSet(chaoskube, "default", "other", "other")
+
+		for name, rs := range map[string]string{
+			"chaoskube-old-a": "chaoskube-old",
+			"chaoskube-new-a": "chaoskube-new",
+			"chaoskube-new-b": "chaoskube-new",
+			"other-a":         "other",
+			"bare":            "",
+		} {
+			pod := util.NewPod("default", name)
+			if rs != "" {
+				setOwner(&pod, "ReplicaSet", rs)
+			}
+			_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pod)
+			suite.Require().NoError(err)
+		}
+
+		pods, err := chaoskube.Candidates()
+		suite.Require().NoError(err)
+
+		names := []string{}
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		suite.ElementsMatch(tt.expected, names)
+
+		warning := false
+		for _, entry := range logOutput.AllEntries() {
+			if entry.Level == log.WarnLevel && strings.HasPrefix(entry.Message, "Failed to look up own pod") {
+				warning = true
+			}
+		}
+		suite.Equal(tt.warning, warning, tt.selfName)
+	}
+}
+
+// TestResolveSelf tests that an unknown own pod is fine but one that can't be looked up isn't
+func (suite *Suite) TestResolveSelf() {
+	for _, tt := range []struct {
+		selfName string
+		err      bool
+	}{
+		{"", false},
+		{"foo", false},
+		{"gone", true},
+	} {
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		if tt.selfName != "" {
+			chaoskube.SelfNamespace = "default"
+			chaoskube.SelfName = tt.selfName
+		}
+
+		err := chaoskube.ResolveSelf()
+		suite.Equal(tt.err, err != nil, tt.selfName)
+	}
+}
+
+// TestCandidatesMinReplicas tests that pods of small workloads and bare pods can be excluded
+func (suite *Suite) TestCandidatesMinReplicas() {
+	for _, tt := range []struct {
+		minReplicas     int
+		excludeBarePods bool
+		expected        []string
+	}{
+		{0, false, []string{"bare", "small-1", "large-1", "job-1"}},
+		{0, true, []string{"small-1", "large-1", "job-1"}},
+		{2, false, []string{"bare", "large-1"}},
+		{3, true, []string{"large-1"}},
+		{4, true, []string{}},
+	} {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		chaoskube.MinReplicas = tt.minReplicas
+		chaoskube.ExcludeBarePods = tt.excludeBarePods
+
+		for name, replicas := range map[string]int32{"small": 1, "large": 3} {
+			replicas := replicas
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+			}
+			_, err := chaoskube.Client.AppsV1().StatefulSets("default").Create(sts)
+			suite.Require().NoError(err)
+		}
+
+		for _, owner := range []struct{ kind, name string }{{"", "bare"}, {"StatefulSet", "small"}, {"StatefulSet", "large"}, {"Job", "job"}} {
+			pod := util.NewPod("default", owner.name)
+			if owner.kind != "" {
+				pod.Name = owner.name + "-1"
+				setOwner(&pod, owner.kind, owner.name)
+			}
+			_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pod)
+			suite.Require().NoError(err)
+		}
+
+		pods, err := chaoskube.Candidates()
+		suite.Require().NoError(err)
+
+		names := []string{}
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		suite.ElementsMatch(tt.expected, names)
+	}
+}
+
+// TestWorkloadCache tests that each workload is looked up and namespaces are listed at most once per run
+func (suite *Suite) TestWorkloadCache() {
+	chaoskube := suite.setup(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+	chaoskube.MinReplicas = 1
+	suite.createReplicaSet(chaoskube, "default", "foo-1234", "")
+
+	for _, name := range []string{"foo-1234-a", "foo-1234-b", "foo-1234-c"} {
+		pod := util.NewPod("default", name)
+		setOwner(&pod, "ReplicaSet", "foo-1234")
+		_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pod)
+		suite.Require().NoError(err)
+	}
+
+	client := chaoskube.Client.(*fake.Clientset)
+	client.ClearActions()
+
+	pods, err := chaoskube.Candidates()
+	suite.Require().NoError(err)
+	suite.Len(pods, 3)
+
+	calls := map[string]int{}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "get" || action.GetResource().Resource == "namespaces" {
+			calls[action.GetVerb()+" "+action.GetResource().Resource]++
+		}
+	}
+	suite.Equal(map[string]int{"get replicasets": 1, "list namespaces": 1}, calls)
+}
+
+// TestVictimsGroupByOwner tests that at most one pod per workload is picked
+func (suite *Suite) TestVictimsGroupByOwner() {
+	chaoskube := suite.setup(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+	chaoskube.GroupByOwner = true
+	chaoskube.VictimCount = 3
+
+	for _, name := range []string{"foo-1", "foo-2", "foo-3", "bar-1"} {
+		pod := util.NewPod("default", name)
+		setOwner(&pod, "StatefulSet", name[:3])
+
+		_, err := chaoskube.Client.CoreV1().Pods(pod.Namespace).Create(&pod)
+		suite.Require().NoError(err)
+	}
+
+	victims, err := chaoskube.Victims()
+	suite.Require().NoError(err)
+	suite.Require().Len(victims, 2)
+
+	owners := map[string]bool{}
+	for _, victim := range victims {
+		owners[chaoskube.Owner(victim).Name] = true
+	}
+	suite.Equal(map[string]bool{"foo": true, "bar": true}, owners)
+}
+
 // TestNoVictimReturnsError tests that on missing victim it returns a known error
 func (suite *Suite) TestNoVictimReturnsError() {
 	chaoskube := suite.setup(
@@ -187,16 +1160,109 @@ func (suite *Suite) TestDeletePod() {
 			tt.dryRun,
 		)
 
-		victim := util.NewPod("default", "foo")
+		victim := util.NewPod("default", "foo")
+
+		err := chaoskube.DeletePod(victim)
+		suite.Require().NoError(err)
+
+		suite.assertLog(log.InfoLevel, "terminating pod", log.Fields{"namespace": "default", "name": "foo"})
+		suite.assertCandidates(chaoskube, tt.remainingPods)
+	}
+}
+
+// TestDeletePodTerminationMode tests that the configured or annotated termination mode is used
+func (suite *Suite) TestDeletePodTerminationMode() {
+	for _, tt := range []struct {
+		mode       string
+		annotation string
+		expected   string
+	}{
+		{"graceful", "", "graceful"},
+		{"immediate", "", "immediate"},
+		{"30s", "", "30s"},
+		{"graceful", "immediate", "immediate"},
+		{"immediate", "10s", "10s"},
+		{"30s", "invalid", "30s"},
+	} {
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+
+		mode, err := ParseTerminationMode(tt.mode)
+		suite.Require().NoError(err)
+		chaoskube.TerminationMode = mode
+
+		victim := util.NewPod("default", "foo")
+		if tt.annotation != "" {
+			victim.Annotations[TerminationModeAnnotation] = tt.annotation
+		}
+
+		err = chaoskube.DeletePod(victim)
+		suite.Require().NoError(err)
+
+		suite.assertLog(log.InfoLevel, "terminating pod", log.Fields{"termination-mode": tt.expected})
+		suite.assertCandidates(chaoskube, []map[string]string{{"namespace": "testing", "name": "bar"}})
+	}
+}
+
+func (suite *Suite) TestParseTerminationMode() {
+	zero := int64(0)
+	thirty := int64(30)
+	background := metav1.DeletePropagationBackground
 
-		err := chaoskube.DeletePod(victim)
+	for _, tt := range []struct {
+		given    string
+		expected *metav1.DeleteOptions
+	}{
+		{"", nil},
+		{"graceful", nil},
+		{" Immediate ", &metav1.DeleteOptions{GracePeriodSeconds: &zero, PropagationPolicy: &background}},
+		{"30s", &metav1.DeleteOptions{GracePeriodSeconds: &thirty}},
+		{"0s", &metav1.DeleteOptions{GracePeriodSeconds: &zero}},
+	} {
+		mode, err := ParseTerminationMode(tt.given)
 		suite.Require().NoError(err)
 
-		suite.assertLog(log.InfoLevel, "terminating pod", log.Fields{"namespace": "default", "name": "foo"})
-		suite.assertCandidates(chaoskube, tt.remainingPods)
+		suite.Equal(tt.expected, mode.DeleteOptions())
+	}
+
+	for _, given := range []string{"forceful", "-5s", "500ms", "1.5s"} {
+		_, err := ParseTerminationMode(given)
+		suite.Error(err)
 	}
 }
 
+// TestDeletePodLogsWeight tests that the victim's weight is logged in weighted mode
+func (suite *Suite) TestDeletePodLogsWeight() {
+	chaoskube := suite.setupWithPods(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+	chaoskube.WeightAnnotation = "chaos.weight"
+	chaoskube.DefaultWeight = 1
+
+	victim := util.NewPod("default", "foo")
+	victim.Annotations["chaos.weight"] = "5"
+
+	err := chaoskube.DeletePod(victim)
+	suite.Require().NoError(err)
+
+	suite.assertLog(log.InfoLevel, "terminating pod", log.Fields{"namespace": "default", "name": "foo", "weight": 5})
+}
+
 func (suite *Suite) TestTerminateVictim() {
 	midnight := util.NewTimePeriod(
 		ThankGodItsFriday{}.Now().Add(-16*time.Hour),
@@ -335,98 +1401,883 @@ func (suite *Suite) TestTerminateVictim() {
 			[]time.Weekday{},
 			[]util.TimePeriod{midnight},
 			[]time.Time{},
-			func() time.Time { return ThankGodItsFriday{}.Now().Add(-17 * time.Hour) },
+			func() time.Time { return ThankGodItsFriday{}.Now().Add(-17 * time.Hour) },
+			time.UTC,
+			1,
+		},
+		// we're after an excluded time period across days, one pod should be killed
+		{
+			[]time.Weekday{},
+			[]util.TimePeriod{midnight},
+			[]time.Time{},
+			func() time.Time { return ThankGodItsFriday{}.Now().Add(-13 * time.Hour) },
+			time.UTC,
+			1,
+		},
+		// this day of year is excluded, no pod should be killed
+		{
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{
+				ThankGodItsFriday{}.Now(), // today
+			},
+			func() time.Time { return ThankGodItsFriday{}.Now() },
+			time.UTC,
+			2,
+		},
+		// this day of year in year 0 is excluded, no pod should be killed
+		{
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{
+				time.Date(0, 9, 24, 0, 00, 00, 00, time.UTC), // same year day
+			},
+			func() time.Time { return ThankGodItsFriday{}.Now() },
+			time.UTC,
+			2,
+		},
+		// matching works fine even when multiple days-of-year are provided, no pod should be killed
+		{
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{
+				time.Date(0, 9, 25, 10, 00, 00, 00, time.UTC), // different year day
+				time.Date(0, 9, 24, 10, 00, 00, 00, time.UTC), // same year day
+			},
+			func() time.Time { return ThankGodItsFriday{}.Now() },
+			time.UTC,
+			2,
+		},
+		// there is an excluded day of year but it's not today, one pod should be killed
+		{
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{
+				time.Date(0, 9, 25, 10, 00, 00, 00, time.UTC), // different year day
+			},
+			func() time.Time { return ThankGodItsFriday{}.Now() },
+			time.UTC,
+			1,
+		},
+		// there is an excluded day of year but the month is different, one pod should be killed
+		{
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{
+				time.Date(0, 10, 24, 10, 00, 00, 00, time.UTC), // different year day
+			},
+			func() time.Time { return ThankGodItsFriday{}.Now() },
+			time.UTC,
+			1,
+		},
+	} {
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			tt.excludedWeekdays,
+			tt.excludedTimesOfDay,
+			tt.excludedDaysOfYear,
+			tt.timezone,
+			false,
+		)
+		chaoskube.Now = tt.now
+
+		err := chaoskube.TerminateVictim()
+		suite.Require().NoError(err)
+
+		pods, err := chaoskube.Candidates()
+		suite.Require().NoError(err)
+
+		suite.Len(pods, tt.remainingPodCount)
+	}
+}
+
+// TestTerminateMultipleVictims tests that all victims of a run are deleted
+func (suite *Suite) TestTerminateMultipleVictims() {
+	chaoskube := suite.setupWithPods(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+	chaoskube.VictimCount = 2
+
+	err := chaoskube.TerminateVictim()
+	suite.Require().NoError(err)
+
+	suite.assertCandidates(chaoskube, []map[string]string{})
+}
+
+// TestTerminateVictimSkipsStalePods tests that pods which are gone but still cached aren't terminated
+func (suite *Suite) TestTerminateVictimSkipsStalePods() {
+	for _, tt := range []struct {
+		cached   []v1.Pod
+		expected []string
+	}{
+		// the cached pod is gone
+		{[]v1.Pod{util.NewPod("default", "gone")}, []string{"foo", "bar"}},
+		// the cached pod was replaced by a new one with the same name
+		{[]v1.Pod{util.NewPod("default", "foo")}, []string{"foo", "bar"}},
+		// the cached pod is still there
+		{[]v1.Pod{util.NewPod("default", "bar")}, []string{"foo"}},
+		// another candidate is tried after a stale one
+		{[]v1.Pod{util.NewPod("default", "gone"), util.NewPod("default", "bar")}, []string{"foo"}},
+	} {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+
+		for _, name := range []string{"foo", "bar"} {
+			pod := util.NewPod("default", name)
+			pod.UID = types.UID(name)
+			_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pod)
+			suite.Require().NoError(err)
+		}
+
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		for i := range tt.cached {
+			pod := tt.cached[i]
+			if pod.Name == "bar" {
+				pod.UID = types.UID(pod.Name)
+			}
+			suite.Require().NoError(indexer.Add(&pod))
+		}
+		chaoskube.PodLister = corelisters.NewPodLister(indexer)
+
+		err := chaoskube.TerminateVictim()
+		suite.Require().NoError(err)
+
+		remaining, err := chaoskube.Client.CoreV1().Pods("default").List(metav1.ListOptions{})
+		suite.Require().NoError(err)
+
+		names := []string{}
+		for _, pod := range remaining.Items {
+			names = append(names, pod.Name)
+		}
+		suite.ElementsMatch(tt.expected, names)
+	}
+}
+
+// TestTerminateVictimWithEviction tests that pods protected by a disruption budget are skipped
+func (suite *Suite) TestTerminateVictimWithEviction() {
+	for _, tt := range []struct {
+		protected []string
+		evicted   []string
+		blocked   int64
+	}{
+		{[]string{}, []string{"default"}, 0},
+		{[]string{"default"}, []string{"testing"}, 1},
+		{[]string{"default", "testing"}, []string{}, 2},
+	} {
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		chaoskube.UseEviction = true
+
+		// picks foo first
+		chaoskube.Rand = rand.New(rand.NewSource(2000))
+
+		evicted := []string{}
+		chaoskube.Client.(*fake.Clientset).PrependReactor("post", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "eviction" {
+				return false, nil, nil
+			}
+			for _, ns := range tt.protected {
+				if ns == action.GetNamespace() {
+					return true, nil, apierrors.NewTooManyRequests("disruption budget", 0)
+				}
+			}
+			evicted = append(evicted, action.GetNamespace())
+			return true, nil, nil
+		})
+
+		err := chaoskube.TerminateVictim()
+		suite.Require().NoError(err)
+
+		suite.Equal(tt.evicted, evicted)
+		suite.Equal(tt.blocked, chaoskube.Stats.BlockedEvictions())
+	}
+}
+
+// TestStats tests that counts add up and can be shared across instances
+func (suite *Suite) TestStats() {
+	stats := NewStats()
+	suite.Equal(StatsSnapshot{}, stats.Snapshot())
+
+	stats.AddBlockedEvictions(2)
+	stats.AddBlockedEvictions(3)
+	suite.Equal(StatsSnapshot{BlockedEvictions: 5}, stats.Snapshot())
+}
+
+// TestTerminateVictimWithBlockedEvictions tests that pods replacing blocked evictions are distinct
+func (suite *Suite) TestTerminateVictimWithBlockedEvictions() {
+	for seed := int64(0); seed < 20; seed++ {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		chaoskube.UseEviction = true
+		chaoskube.VictimCount = 3
+		chaoskube.Rand = rand.New(rand.NewSource(seed))
+
+		for _, ns := range []string{"ns1", "ns2", "ns3", "ns4"} {
+			pod := util.NewPod(ns, "foo")
+			_, err := chaoskube.Client.CoreV1().Pods(ns).Create(&pod)
+			suite.Require().NoError(err)
+		}
+
+		evicted := []string{}
+		chaoskube.Client.(*fake.Clientset).PrependReactor("post", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+			if action.GetSubresource() != "eviction" {
+				return false, nil, nil
+			}
+			if action.GetNamespace() == "ns1" || action.GetNamespace() == "ns2" {
+				return true, nil, apierrors.NewTooManyRequests("disruption budget", 0)
+			}
+			evicted = append(evicted, action.GetNamespace())
+			return true, nil, nil
+		})
+
+		err := chaoskube.TerminateVictim()
+		suite.Require().NoError(err)
+
+		suite.ElementsMatch([]string{"ns3", "ns4"}, evicted, "seed %d", seed)
+		suite.Equal(int64(2), chaoskube.Stats.BlockedEvictions(), "seed %d", seed)
+	}
+}
+
+// TestTerminateVictimChecksWorkloadHealth tests that pods of unhealthy workloads are spared
+func (suite *Suite) TestTerminateVictimChecksWorkloadHealth() {
+	for _, tt := range []struct {
+		ready             int32
+		updated           int32
+		remainingPodCount int
+		skipReason        string
+	}{
+		{3, 3, 0, ""},
+		{2, 3, 1, "only 2 of 3 replicas ready"},
+		{3, 2, 1, "rollout in progress"},
+	} {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
+			false,
+		)
+		chaoskube.CheckWorkloadHealth = true
+
+		replicas := int32(3)
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				Replicas:        3,
+				ReadyReplicas:   tt.ready,
+				UpdatedReplicas: tt.updated,
+			},
+		}
+		_, err := chaoskube.Client.AppsV1().Deployments("default").Create(deployment)
+		suite.Require().NoError(err)
+
+		suite.createReplicaSet(chaoskube, "default", "foo-1234", "foo")
+
+		pod := util.NewPod("default", "foo-1234-abcd")
+		setOwner(&pod, "ReplicaSet", "foo-1234")
+		_, err = chaoskube.Client.CoreV1().Pods("default").Create(&pod)
+		suite.Require().NoError(err)
+
+		err = chaoskube.TerminateVictim()
+		suite.Require().NoError(err)
+
+		pods, err := chaoskube.Candidates()
+		suite.Require().NoError(err)
+		suite.Len(pods, tt.remainingPodCount)
+
+		if tt.skipReason != "" {
+			suite.assertLog(log.InfoLevel, msgWorkloadUnhealthy, log.Fields{"skip-reason": tt.skipReason})
+		}
+	}
+}
+
+// TestTerminateVictimChecksWorkloadHealthWithinRun tests that pods killed earlier in a run count against their workload's health
+func (suite *Suite) TestTerminateVictimChecksWorkloadHealthWithinRun() {
+	chaoskube := suite.setup(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+	chaoskube.CheckWorkloadHealth = true
+	chaoskube.VictimCount = 3
+
+	replicas := int32(3)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			Replicas:        3,
+			ReadyReplicas:   3,
+			UpdatedReplicas: 3,
+		},
+	}
+	_, err := chaoskube.Client.AppsV1().Deployments("default").Create(deployment)
+	suite.Require().NoError(err)
+
+	suite.createReplicaSet(chaoskube, "default", "foo-1234", "foo")
+
+	for i := 0; i < 3; i++ {
+		pod := util.NewPod("default", fmt.Sprintf("foo-1234-%d", i))
+		setOwner(&pod, "ReplicaSet", "foo-1234")
+		_, err = chaoskube.Client.CoreV1().Pods("default").Create(&pod)
+		suite.Require().NoError(err)
+	}
+
+	err = chaoskube.TerminateVictim()
+	suite.Require().NoError(err)
+
+	pods, err := chaoskube.Candidates()
+	suite.Require().NoError(err)
+	suite.Len(pods, 2)
+
+	suite.assertLog(log.InfoLevel, msgWorkloadUnhealthy, log.Fields{"skip-reason": "only 2 of 3 replicas ready"})
+}
+
+func (suite *Suite) TestStatefulSetHealth() {
+	replicas := int32(2)
+
+	for _, tt := range []struct {
+		status   appsv1.StatefulSetStatus
+		expected string
+	}{
+		{appsv1.StatefulSetStatus{ReadyReplicas: 2, CurrentRevision: "a", UpdateRevision: "a"}, ""},
+		{appsv1.StatefulSetStatus{ReadyReplicas: 1, CurrentRevision: "a", UpdateRevision: "a"}, "only 1 of 2 replicas ready"},
+		{appsv1.StatefulSetStatus{ReadyReplicas: 2, CurrentRevision: "a", UpdateRevision: "b"}, "rollout in progress"},
+	} {
+		sts := &appsv1.StatefulSet{
+			Spec:   appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status: tt.status,
+		}
+
+		suite.Equal(tt.expected, statefulSetHealth(sts, 0))
+	}
+}
+
+// TestTerminateVictimAllowedWindows tests that pods are only killed within the allowed windows
+func (suite *Suite) TestTerminateVictimAllowedWindows() {
+	for _, tt := range []struct {
+		allowedWindows    string
+		excludedWeekdays  []time.Weekday
+		remainingPodCount int
+	}{
+		// no windows allow any time
+		{"", []time.Weekday{}, 1},
+		// within the window
+		{"Mon-Fri 10:00-16:00", []time.Weekday{}, 1},
+		// outside of the window's time periods
+		{"Mon-Fri 10:00-12:00", []time.Weekday{}, 2},
+		// outside of the window's weekdays
+		{"Sat-Sun 10:00-16:00", []time.Weekday{}, 2},
+		// within one of several windows
+		{"Mon-Thu 10:00-16:00;Fri 14:00-16:00", []time.Weekday{}, 1},
+		// exclusions still apply
+		{"Mon-Fri 10:00-16:00", []time.Weekday{time.Friday}, 2},
+	} {
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
+			tt.excludedWeekdays,
+			[]util.TimePeriod{},
+			[]time.Time{},
 			time.UTC,
-			1,
-		},
-		// we're after an excluded time period across days, one pod should be killed
-		{
+			false,
+		)
+		chaoskube.Now = ThankGodItsFriday{}.Now
+
+		var err error
+		chaoskube.AllowedWindows, err = util.ParseTimeWindows(tt.allowedWindows)
+		suite.Require().NoError(err)
+
+		err = chaoskube.TerminateVictim()
+		suite.Require().NoError(err)
+
+		pods, err := chaoskube.Candidates()
+		suite.Require().NoError(err)
+		suite.Len(pods, tt.remainingPodCount, tt.allowedWindows)
+	}
+}
+
+// TestTerminateVictimBlackouts tests that no victims are terminated during blackouts
+func (suite *Suite) TestTerminateVictimBlackouts() {
+	for _, tt := range []struct {
+		blackouts         string
+		remainingPodCount int
+		expectedLog       string
+	}{
+		// no blackouts
+		{"", 1, ""},
+		// within a range of dates
+		{"freeze=1869-09-20..1869-09-30", 2, "Day [1869-09-24] is within blackout [freeze=1869-09-20..1869-09-30]"},
+		// the same days in another year
+		{"freeze=2026-09-20..2026-09-30", 1, ""},
+		// within an annual range
+		{"Sep24", 2, "Day [1869-09-24] is within blackout [Sep24]"},
+		// outside of an annual range wrapping around the end of the year
+		{"holidays=Dec20..Jan5", 1, ""},
+	} {
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
 			[]time.Weekday{},
-			[]util.TimePeriod{midnight},
+			[]util.TimePeriod{},
 			[]time.Time{},
-			func() time.Time { return ThankGodItsFriday{}.Now().Add(-13 * time.Hour) },
 			time.UTC,
-			1,
-		},
-		// this day of year is excluded, no pod should be killed
-		{
+			false,
+		)
+		chaoskube.Now = ThankGodItsFriday{}.Now
+
+		var err error
+		chaoskube.Blackouts, err = util.ParseBlackouts(tt.blackouts)
+		suite.Require().NoError(err)
+
+		err = chaoskube.TerminateVictim()
+		suite.Require().NoError(err)
+
+		if tt.expectedLog != "" {
+			suite.assertLog(log.InfoLevel, tt.expectedLog, log.Fields{})
+		}
+
+		pods, err := chaoskube.Candidates()
+		suite.Require().NoError(err)
+		suite.Len(pods, tt.remainingPodCount, tt.blackouts)
+	}
+}
+
+// TestTerminateVictimExcludedWindows tests that no victims are terminated within weekday-specific excluded windows
+func (suite *Suite) TestTerminateVictimExcludedWindows() {
+	for _, tt := range []struct {
+		excludedWindows   string
+		remainingPodCount int
+	}{
+		// no windows exclude nothing
+		{"", 1},
+		// within the window
+		{"Fri 14:00-23:59", 2},
+		// same time on another weekday
+		{"Mon 14:00-23:59", 1},
+		// outside of the window's time periods
+		{"Fri 00:00-10:00", 1},
+		// within one of several windows
+		{"Mon 00:00-10:00;Fri 14:00-23:59", 2},
+		// a period crossing midnight belongs to the day it starts on
+		{"Thu 22:00-16:00", 2},
+		{"Fri 22:00-16:00", 1},
+	} {
+		chaoskube := suite.setupWithPods(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
 			[]time.Weekday{},
 			[]util.TimePeriod{},
-			[]time.Time{
-				ThankGodItsFriday{}.Now(), // today
-			},
-			func() time.Time { return ThankGodItsFriday{}.Now() },
+			[]time.Time{},
 			time.UTC,
-			2,
-		},
-		// this day of year in year 0 is excluded, no pod should be killed
-		{
+			false,
+		)
+		chaoskube.Now = ThankGodItsFriday{}.Now
+
+		var err error
+		chaoskube.ExcludedWindows, err = util.ParseWeekdayTimePeriods(tt.excludedWindows)
+		suite.Require().NoError(err)
+
+		err = chaoskube.TerminateVictim()
+		suite.Require().NoError(err)
+
+		pods, err := chaoskube.Candidates()
+		suite.Require().NoError(err)
+		suite.Len(pods, tt.remainingPodCount, tt.excludedWindows)
+	}
+}
+
+// TestNextRun tests that the next run is due according to the schedule in the configured timezone
+func (suite *Suite) TestNextRun() {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	suite.Require().NoError(err)
+
+	for _, tt := range []struct {
+		schedule string
+		timezone *time.Location
+		expected time.Time
+	}{
+		// no schedule
+		{"", time.UTC, time.Time{}},
+		// later on the same day
+		{"*/15 9-16 * * Mon-Fri", time.UTC, time.Date(1869, 9, 24, 15, 15, 0, 0, time.UTC)},
+		// it's already 16:04 in Berlin, so office hours are over until Monday
+		{"*/15 9-15 * * Mon-Fri", berlin, time.Date(1869, 9, 27, 9, 0, 0, 0, berlin)},
+	} {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
 			[]time.Weekday{},
 			[]util.TimePeriod{},
-			[]time.Time{
-				time.Date(0, 9, 24, 0, 00, 00, 00, time.UTC), // same year day
-			},
-			func() time.Time { return ThankGodItsFriday{}.Now() },
-			time.UTC,
-			2,
-		},
-		// matching works fine even when multiple days-of-year are provided, no pod should be killed
-		{
+			[]time.Time{},
+			tt.timezone,
+			false,
+		)
+		chaoskube.Now = ThankGodItsFriday{}.Now
+
+		if tt.schedule != "" {
+			chaoskube.Schedule, err = util.ParseSchedule(tt.schedule)
+			suite.Require().NoError(err)
+		}
+
+		suite.True(tt.expected.Equal(chaoskube.NextRun()), "%s: %v", tt.schedule, chaoskube.NextRun())
+	}
+}
+
+// TestNextInterval tests that the time between runs follows the configured distribution and bounds
+func (suite *Suite) TestNextInterval() {
+	for _, tt := range []struct {
+		distribution string
+		jitter       int
+		min, max     time.Duration
+		lowest       time.Duration
+		highest      time.Duration
+	}{
+		{IntervalFixed, 0, 0, 0, 10 * time.Minute, 10 * time.Minute},
+		{IntervalJitter, 20, 0, 0, 8 * time.Minute, 12 * time.Minute},
+		{IntervalJitter, 20, 9 * time.Minute, 11 * time.Minute, 9 * time.Minute, 11 * time.Minute},
+		// varied intervals are at least a tenth of the interval by default
+		{IntervalJitter, 100, 0, 0, time.Minute, 20 * time.Minute},
+		{IntervalExponential, 0, 0, 0, time.Minute, 24 * time.Hour},
+		{IntervalExponential, 0, time.Minute, time.Hour, time.Minute, time.Hour},
+	} {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
 			[]time.Weekday{},
 			[]util.TimePeriod{},
-			[]time.Time{
-				time.Date(0, 9, 25, 10, 00, 00, 00, time.UTC), // different year day
-				time.Date(0, 9, 24, 10, 00, 00, 00, time.UTC), // same year day
-			},
-			func() time.Time { return ThankGodItsFriday{}.Now() },
+			[]time.Time{},
 			time.UTC,
-			2,
-		},
-		// there is an excluded day of year but it's not today, one pod should be killed
-		{
+			false,
+		)
+		chaoskube.IntervalRand = rand.New(rand.NewSource(42))
+		chaoskube.Interval = 10 * time.Minute
+		chaoskube.IntervalDistribution = tt.distribution
+		chaoskube.IntervalJitter = tt.jitter
+		chaoskube.MinInterval = tt.min
+		chaoskube.MaxInterval = tt.max
+
+		var total time.Duration
+		distinct := map[time.Duration]bool{}
+
+		for i := 0; i < 10000; i++ {
+			interval := chaoskube.NextInterval()
+			suite.True(interval >= tt.lowest && interval <= tt.highest, "%s: %v", tt.distribution, interval)
+
+			total += interval
+			distinct[interval] = true
+		}
+
+		if tt.distribution == IntervalFixed {
+			suite.Len(distinct, 1)
+		} else {
+			suite.True(len(distinct) > 1)
+		}
+
+		// distributions bounded by nothing but the default minimum keep about the interval as their mean
+		if tt.min == 0 && tt.max == 0 {
+			mean := total / 10000
+			suite.InDelta(float64(10*time.Minute), float64(mean), float64(30*time.Second), tt.distribution)
+		}
+	}
+}
+
+// TestNextIntervalKeepsVictims tests that varying intervals doesn't change the sequence of victims
+func (suite *Suite) TestNextIntervalKeepsVictims() {
+	chaoskube := suite.setup(
+		labels.Everything(),
+		labels.Everything(),
+		labels.Everything(),
+		[]time.Weekday{},
+		[]util.TimePeriod{},
+		[]time.Time{},
+		time.UTC,
+		false,
+	)
+	chaoskube.Rand = rand.New(rand.NewSource(42))
+	chaoskube.Interval = 10 * time.Minute
+	chaoskube.IntervalDistribution = IntervalExponential
+
+	chaoskube.NextInterval()
+
+	suite.Equal(rand.New(rand.NewSource(42)).Int63(), chaoskube.Rand.Int63())
+	suite.NotEqual(int64(42), IntervalSeed(42))
+}
+
+// TestValidateInterval tests that invalid interval distributions are rejected
+func (suite *Suite) TestValidateInterval() {
+	for _, tt := range []struct {
+		distribution string
+		jitter       int
+		min, max     time.Duration
+		valid        bool
+	}{
+		{IntervalFixed, 0, 0, 0, true},
+		{"", 0, 0, 0, true},
+		{IntervalJitter, 50, 0, 0, true},
+		{IntervalJitter, 101, 0, 0, false},
+		{IntervalJitter, -1, 0, 0, false},
+		{IntervalExponential, 0, time.Minute, time.Hour, true},
+		{IntervalExponential, 0, time.Hour, time.Minute, false},
+		{IntervalExponential, 0, -time.Minute, 0, false},
+		{"gaussian", 0, 0, 0, false},
+	} {
+		err := ValidateInterval(tt.distribution, tt.jitter, tt.min, tt.max)
+		if tt.valid {
+			suite.NoError(err)
+		} else {
+			suite.Error(err)
+		}
+	}
+}
+
+// TestTerminateFailureDomain tests that all candidates of a single failure domain are terminated at once
+func (suite *Suite) TestTerminateFailureDomain() {
+	zoneLabel := "topology.kubernetes.io/zone"
+
+	for _, tt := range []struct {
+		failureDomain string
+		maxVictims    int
+		killed        int
+	}{
+		{"node", 0, 2},
+		{zoneLabel, 0, 4},
+		{zoneLabel, 3, 3},
+	} {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
 			[]time.Weekday{},
 			[]util.TimePeriod{},
-			[]time.Time{
-				time.Date(0, 9, 25, 10, 00, 00, 00, time.UTC), // different year day
-			},
-			func() time.Time { return ThankGodItsFriday{}.Now() },
+			[]time.Time{},
 			time.UTC,
-			1,
-		},
-		// there is an excluded day of year but the month is different, one pod should be killed
-		{
+			false,
+		)
+		chaoskube.FailureDomain = tt.failureDomain
+		chaoskube.MaxVictims = tt.maxVictims
+
+		// two zones with two nodes each running two pods each, plus an unscheduled pod
+		zones := map[string]string{}
+		for _, name := range []string{"a-1", "a-2", "b-1", "b-2"} {
+			zones[name] = name[:1]
+			node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{zoneLabel: name[:1]}}}
+			_, err := chaoskube.Client.CoreV1().Nodes().Create(node)
+			suite.Require().NoError(err)
+
+			for i := 0; i < 2; i++ {
+				pod := util.NewPod("default", fmt.Sprintf("%s-%d", name, i))
+				pod.Spec.NodeName = name
+				_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pod)
+				suite.Require().NoError(err)
+			}
+		}
+		pending := util.NewPod("default", "pending")
+		_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pending)
+		suite.Require().NoError(err)
+
+		err = chaoskube.TerminateVictim()
+		suite.Require().NoError(err)
+
+		remaining, err := chaoskube.Client.CoreV1().Pods("default").List(metav1.ListOptions{})
+		suite.Require().NoError(err)
+		suite.Len(remaining.Items, 9-tt.killed)
+
+		// all victims come from the same domain
+		killed := map[string]int{}
+		for name, zone := range zones {
+			domain := name
+			if tt.failureDomain == zoneLabel {
+				domain = zone
+			}
+			killed[domain] += 2
+		}
+		for _, pod := range remaining.Items {
+			domain := pod.Spec.NodeName
+			if domain == "" {
+				continue
+			}
+			if tt.failureDomain == zoneLabel {
+				domain = zones[domain]
+			}
+			killed[domain]--
+		}
+		hit := 0
+		for _, count := range killed {
+			if count > 0 {
+				hit++
+				suite.Equal(tt.killed, count)
+			}
+		}
+		suite.Equal(1, hit)
+
+		suite.assertLog(log.InfoLevel, msgFailureDomainTerminated, log.Fields{"failure-domain": tt.failureDomain})
+	}
+}
+
+// TestTerminateVictimCooldown tests that recently hit workloads and nodes are spared
+func (suite *Suite) TestTerminateVictimCooldown() {
+	for _, tt := range []struct {
+		workloadCooldown  time.Duration
+		nodeCooldown      time.Duration
+		elapsed           time.Duration
+		remainingPodCount int
+	}{
+		{0, 0, 0, 0},
+		{time.Hour, 0, 30 * time.Minute, 1},
+		{time.Hour, 0, 2 * time.Hour, 0},
+		{0, time.Hour, 30 * time.Minute, 1},
+		{0, time.Hour, 2 * time.Hour, 0},
+	} {
+		chaoskube := suite.setup(
+			labels.Everything(),
+			labels.Everything(),
+			labels.Everything(),
 			[]time.Weekday{},
 			[]util.TimePeriod{},
-			[]time.Time{
-				time.Date(0, 10, 24, 10, 00, 00, 00, time.UTC), // different year day
-			},
-			func() time.Time { return ThankGodItsFriday{}.Now() },
+			[]time.Time{},
 			time.UTC,
-			1,
-		},
+			false,
+		)
+		chaoskube.Now = ThankGodItsFriday{}.Now
+		chaoskube.WorkloadCooldown = tt.workloadCooldown
+		chaoskube.NodeCooldown = tt.nodeCooldown
+
+		// two replicas of the same workload on the same node
+		pods := []v1.Pod{}
+		for i := 0; i < 2; i++ {
+			pod := util.NewPod("default", fmt.Sprintf("foo-%d", i))
+			pod.Spec.NodeName = "node-a"
+			setOwner(&pod, "StatefulSet", "foo")
+			_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pod)
+			suite.Require().NoError(err)
+			pods = append(pods, pod)
+		}
+
+		suite.Require().NoError(chaoskube.DeletePod(pods[0]))
+
+		chaoskube.Now = func() time.Time { return ThankGodItsFriday{}.Now().Add(tt.elapsed) }
+
+		err := chaoskube.TerminateVictim()
+		suite.Require().NoError(err)
+
+		remaining, err := chaoskube.Client.CoreV1().Pods("default").List(metav1.ListOptions{})
+		suite.Require().NoError(err)
+		suite.Len(remaining.Items, tt.remainingPodCount)
+	}
+}
+
+// TestTerminateVictimCooldownWithinRun tests that a cooldown started in a run spares the workload and node for the rest of it
+func (suite *Suite) TestTerminateVictimCooldownWithinRun() {
+	for _, tt := range []struct {
+		workloadCooldown  time.Duration
+		nodeCooldown      time.Duration
+		remainingPodCount int
+	}{
+		{0, 0, 0},
+		{time.Hour, 0, 2},
+		{0, time.Hour, 2},
+		{time.Hour, time.Hour, 2},
 	} {
-		chaoskube := suite.setupWithPods(
+		chaoskube := suite.setup(
 			labels.Everything(),
 			labels.Everything(),
 			labels.Everything(),
-			tt.excludedWeekdays,
-			tt.excludedTimesOfDay,
-			tt.excludedDaysOfYear,
-			tt.timezone,
+			[]time.Weekday{},
+			[]util.TimePeriod{},
+			[]time.Time{},
+			time.UTC,
 			false,
 		)
-		chaoskube.Now = tt.now
+		chaoskube.Now = ThankGodItsFriday{}.Now
+		chaoskube.WorkloadCooldown = tt.workloadCooldown
+		chaoskube.NodeCooldown = tt.nodeCooldown
+		chaoskube.VictimCount = 3
+
+		// three replicas of the same workload on the same node
+		for i := 0; i < 3; i++ {
+			pod := util.NewPod("default", fmt.Sprintf("foo-%d", i))
+			pod.Spec.NodeName = "node-a"
+			setOwner(&pod, "StatefulSet", "foo")
+			_, err := chaoskube.Client.CoreV1().Pods("default").Create(&pod)
+			suite.Require().NoError(err)
+		}
 
 		err := chaoskube.TerminateVictim()
 		suite.Require().NoError(err)
 
-		pods, err := chaoskube.Candidates()
+		remaining, err := chaoskube.Client.CoreV1().Pods("default").List(metav1.ListOptions{})
 		suite.Require().NoError(err)
-
-		suite.Len(pods, tt.remainingPodCount)
+		suite.Len(remaining.Items, tt.remainingPodCount)
 	}
 }
 
+// TestHistoryCooldowns tests that only active cooldowns are listed, sorted by their end
+func (suite *Suite) TestHistoryCooldowns() {
+	now := ThankGodItsFriday{}.Now()
+	history := NewHistory()
+
+	history.CoolDownWorkload(Owner{Kind: "Deployment", Namespace: "default", Name: "foo"}, now.Add(time.Hour))
+	history.CoolDownWorkload(Owner{Kind: "Deployment", Namespace: "default", Name: "bar"}, now.Add(-time.Hour))
+	history.CoolDownNode("node-a", now.Add(time.Minute))
+
+	suite.Equal([]Cooldown{
+		{Type: "node", Name: "node-a", Until: now.Add(time.Minute)},
+		{Type: "workload", Namespace: "default", Name: "Deployment/foo", Until: now.Add(time.Hour)},
+	}, history.Cooldowns(now))
+
+	suite.True(history.WorkloadCoolingDown(Owner{Kind: "Deployment", Namespace: "default", Name: "foo"}, now))
+	suite.False(history.WorkloadCoolingDown(Owner{Kind: "Deployment", Namespace: "default", Name: "bar"}, now))
+	suite.False(history.NodeCoolingDown("node-a", now.Add(time.Hour)))
+}
+
 // TestTerminateNoVictimLogsInfo tests that missing victim prints a log message
 func (suite *Suite) TestTerminateNoVictimLogsInfo() {
 	chaoskube := suite.setup(
@@ -486,6 +2337,44 @@ func (suite *Suite) assertLog(level log.Level, msg string, fields log.Fields) {
 	}
 }
 
+// createReplicaSet creates a ReplicaSet that is controlled by the given Deployment, if any.
+func (suite *Suite) createReplicaSet(chaoskube *Chaoskube, namespace, name, deployment string) {
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+	}
+	if deployment != "" {
+		setOwner(rs, "Deployment", deployment)
+	}
+
+	_, err := chaoskube.Client.AppsV1().ReplicaSets(namespace).Create(rs)
+	suite.Require().NoError(err)
+}
+
+// setOwner marks the given object as being controlled by the given owner.
+func setOwner(obj metav1.Object, kind, name string) {
+	controller := true
+	obj.SetOwnerReferences([]metav1.OwnerReference{
+		{Kind: kind, Name: name, Controller: &controller},
+	})
+}
+
+// annotatePods sets the given annotation on each named pod to the corresponding value.
+func (suite *Suite) annotatePods(chaoskube *Chaoskube, key string, values map[string]string) {
+	pods, err := chaoskube.Candidates()
+	suite.Require().NoError(err)
+
+	for _, pod := range pods {
+		value, ok := values[pod.Name]
+		if !ok {
+			continue
+		}
+		pod.Annotations[key] = value
+
+		_, err := chaoskube.Client.CoreV1().Pods(pod.Namespace).Update(&pod)
+		suite.Require().NoError(err)
+	}
+}
+
 func (suite *Suite) setupWithPods(labelSelector labels.Selector, annotations labels.Selector, namespaces labels.Selector, excludedWeekdays []time.Weekday, excludedTimesOfDay []util.TimePeriod, excludedDaysOfYear []time.Time, timezone *time.Location, dryRun bool) *Chaoskube {
 	chaoskube := suite.setup(
 		labelSelector,
@@ -523,8 +2412,10 @@ func (suite *Suite) setup(labelSelector labels.Selector, annotations labels.Sele
 		excludedTimesOfDay,
 		excludedDaysOfYear,
 		timezone,
-		logger,
+		nullLogger,
 		dryRun,
+		false,
+		nil,
 	)
 }
 
@@ -540,3 +2431,8 @@ func (t ThankGodItsFriday) Now() time.Time {
 	blackFriday, _ := time.Parse(time.RFC1123, "Fri, 24 Sep 1869 15:04:05 UTC")
 	return blackFriday
 }
+
+// timePtr returns a pointer to the given time.
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,99 @@
+package chaoskube
+
+import (
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/metrosystems-cpe/chaoskube/datadog"
+)
+
+// FailureDomainNode makes each node a failure domain of its own.
+const FailureDomainNode = "node"
+
+// terminateFailureDomain picks a random failure domain among the given pods and terminates
+// all of its candidates at once, up to MaxVictims. The picked domain and its victims are
+// reported as a single Datadog event.
+func (c *Chaoskube) terminateFailureDomain(pods []v1.Pod) error {
+	domains := c.groupByFailureDomain(pods)
+	if len(domains) == 0 {
+		c.Logger.Debug(msgVictimNotFound)
+		return nil
+	}
+
+	names := make([]string, 0, len(domains))
+	for name := range domains {
+		names = append(names, name)
+	}
+	// keep the order stable so that seeded picks can be reproduced
+	sort.Strings(names)
+
+	domain := names[c.Rand.Intn(len(names))]
+	candidates := domains[domain]
+
+	count := len(candidates)
+	if c.MaxVictims > 0 && count > c.MaxVictims {
+		count = c.MaxVictims
+	}
+
+	c.Logger.WithFields(log.Fields{
+		"failure-domain": c.FailureDomain,
+		"domain":         domain,
+		"candidates":     len(candidates),
+		"victims":        count,
+	}).Info(msgFailureDomainPicked)
+
+	victims, err := c.terminateVictims(candidates, count, false)
+
+	names = make([]string, 0, len(victims))
+	for _, victim := range victims {
+		names = append(names, victim.Namespace+"/"+victim.Name)
+	}
+
+	c.Logger.WithFields(log.Fields{
+		"failure-domain": c.FailureDomain,
+		"domain":         domain,
+		"victims":        names,
+	}).Info(msgFailureDomainTerminated)
+
+	if len(victims) > 0 && !c.DryRun && c.DDEvents {
+		if err := datadog.NewDomainEvent(c.DDClient, c.FailureDomain, domain, victims); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	return err
+}
+
+// groupByFailureDomain groups the given pods by their failure domain. Pods without a known
+// failure domain, e.g. because they aren't scheduled yet, are left out.
+func (c *Chaoskube) groupByFailureDomain(pods []v1.Pod) map[string][]v1.Pod {
+	domains := map[string][]v1.Pod{}
+
+	for _, pod := range pods {
+		domain := c.failureDomain(pod)
+		if domain == "" {
+			c.Logger.Debugf("Skipping pod [%s/%s]: unknown failure domain", pod.Namespace, pod.Name)
+			continue
+		}
+		domains[domain] = append(domains[domain], pod)
+	}
+
+	return domains
+}
+
+// failureDomain returns the failure domain the given pod runs in, or nothing if it's unknown.
+func (c *Chaoskube) failureDomain(pod v1.Pod) string {
+	if pod.Spec.NodeName == "" || c.FailureDomain == FailureDomainNode {
+		return pod.Spec.NodeName
+	}
+
+	node, err := c.node(pod.Spec.NodeName)
+	if err != nil {
+		c.Logger.Debugf("Failed to look up node [%s]: %v", pod.Spec.NodeName, err)
+		return ""
+	}
+
+	return node.Labels[c.FailureDomain]
+}
@@ -0,0 +1,94 @@
+package chaoskube
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// unhealthyReason returns why the workload controlling the given pod shouldn't be disrupted
+// right now, e.g. because it is degraded or in the middle of a rollout. It returns an empty
+// string if the workload is healthy or if the pod isn't controlled by a Deployment, StatefulSet
+// or ReplicaSet. Killed is the number of the workload's pods already terminated in this run,
+// which aren't reflected in the workload's status yet.
+func (c *Chaoskube) unhealthyReason(pod v1.Pod, killed int32) string {
+	ref := metav1.GetControllerOf(&pod)
+	if ref == nil {
+		return ""
+	}
+
+	switch ref.Kind {
+	case "ReplicaSet", "StatefulSet":
+	default:
+		return ""
+	}
+
+	owner := c.Owner(pod)
+
+	obj, err := c.workload(owner.Kind, owner.Namespace, owner.Name)
+	if err != nil {
+		return fmt.Sprintf("failed to look up %s: %v", owner, err)
+	}
+
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return deploymentHealth(workload, killed)
+	case *appsv1.ReplicaSet:
+		return replicaSetHealth(workload, killed)
+	case *appsv1.StatefulSet:
+		return statefulSetHealth(workload, killed)
+	}
+
+	return ""
+}
+
+// deploymentHealth returns why the given Deployment, less the killed pods, is unhealthy or an empty string.
+func deploymentHealth(d *appsv1.Deployment, killed int32) string {
+	desired := desiredReplicas(d.Spec.Replicas)
+
+	if d.Status.ObservedGeneration < d.Generation || d.Status.UpdatedReplicas < desired || d.Status.Replicas > d.Status.UpdatedReplicas {
+		return "rollout in progress"
+	}
+
+	return readiness(d.Status.ReadyReplicas-killed, desired)
+}
+
+// statefulSetHealth returns why the given StatefulSet, less the killed pods, is unhealthy or an empty string.
+func statefulSetHealth(sts *appsv1.StatefulSet, killed int32) string {
+	desired := desiredReplicas(sts.Spec.Replicas)
+
+	if sts.Status.ObservedGeneration < sts.Generation || sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+		return "rollout in progress"
+	}
+
+	return readiness(sts.Status.ReadyReplicas-killed, desired)
+}
+
+// replicaSetHealth returns why the given ReplicaSet, less the killed pods, is unhealthy or an empty string.
+func replicaSetHealth(rs *appsv1.ReplicaSet, killed int32) string {
+	desired := desiredReplicas(rs.Spec.Replicas)
+
+	if rs.Status.ObservedGeneration < rs.Generation {
+		return "rollout in progress"
+	}
+
+	return readiness(rs.Status.ReadyReplicas-killed, desired)
+}
+
+// readiness returns a reason if fewer replicas are ready than desired or an empty string.
+func readiness(ready, desired int32) string {
+	if ready < desired {
+		return fmt.Sprintf("only %d of %d replicas ready", ready, desired)
+	}
+	return ""
+}
+
+// desiredReplicas returns the number of desired replicas, which defaults to one if unset.
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
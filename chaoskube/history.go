@@ -0,0 +1,159 @@
+package chaoskube
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Cooldown describes a workload or node whose pods are immune to termination until a point in time.
+type Cooldown struct {
+	// either "workload" or "node"
+	Type string `json:"type"`
+	// the namespace of the workload, empty for nodes
+	Namespace string `json:"namespace,omitempty"`
+	// the workload, e.g. Deployment/nginx, or the node name
+	Name string `json:"name"`
+	// the point in time the cooldown ends
+	Until time.Time `json:"until"`
+}
+
+// History remembers recently hit workloads and nodes in order to avoid hitting them repeatedly.
+// It is safe for concurrent use.
+type History struct {
+	mutex     sync.Mutex
+	workloads map[Owner]time.Time
+	nodes     map[string]time.Time
+}
+
+// NewHistory returns an empty History.
+func NewHistory() *History {
+	return &History{
+		workloads: map[Owner]time.Time{},
+		nodes:     map[string]time.Time{},
+	}
+}
+
+// CoolDownWorkload makes the given workload immune until the given point in time.
+func (h *History) CoolDownWorkload(owner Owner, until time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.workloads[owner] = until
+}
+
+// CoolDownNode makes the pods on the given node immune until the given point in time.
+func (h *History) CoolDownNode(node string, until time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.nodes[node] = until
+}
+
+// WorkloadCoolingDown returns true iff the given workload is still immune at the given point in time.
+func (h *History) WorkloadCoolingDown(owner Owner, now time.Time) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	until, ok := h.workloads[owner]
+	return ok && now.Before(until)
+}
+
+// NodeCoolingDown returns true iff the given node is still immune at the given point in time.
+func (h *History) NodeCoolingDown(node string, now time.Time) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	until, ok := h.nodes[node]
+	return ok && now.Before(until)
+}
+
+// Cooldowns returns all cooldowns that are active at the given point in time, sorted by their end.
+// It forgets about any cooldowns that have already ended.
+func (h *History) Cooldowns(now time.Time) []Cooldown {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	cooldowns := []Cooldown{}
+
+	for owner, until := range h.workloads {
+		if !now.Before(until) {
+			delete(h.workloads, owner)
+			continue
+		}
+		cooldowns = append(cooldowns, Cooldown{Type: "workload", Namespace: owner.Namespace, Name: owner.String(), Until: until})
+	}
+
+	for node, until := range h.nodes {
+		if !now.Before(until) {
+			delete(h.nodes, node)
+			continue
+		}
+		cooldowns = append(cooldowns, Cooldown{Type: "node", Name: node, Until: until})
+	}
+
+	sort.Slice(cooldowns, func(i, j int) bool {
+		if cooldowns[i].Until.Equal(cooldowns[j].Until) {
+			return cooldowns[i].Name < cooldowns[j].Name
+		}
+		return cooldowns[i].Until.Before(cooldowns[j].Until)
+	})
+
+	return cooldowns
+}
+
+// Cooldowns returns the currently active cooldowns of workloads and nodes.
+func (c *Chaoskube) Cooldowns() []Cooldown {
+	return c.History.Cooldowns(c.Now())
+}
+
+// coolDown records the termination of the given victim to start the configured cooldowns.
+func (c *Chaoskube) coolDown(victim v1.Pod) {
+	now := c.Now()
+
+	if c.WorkloadCooldown > 0 {
+		c.History.CoolDownWorkload(c.Owner(victim), now.Add(c.WorkloadCooldown))
+	}
+
+	if c.NodeCooldown > 0 && victim.Spec.NodeName != "" {
+		c.History.CoolDownNode(victim.Spec.NodeName, now.Add(c.NodeCooldown))
+	}
+}
+
+// filterByCooldown filters out pods whose workload or node is still cooling down.
+func (c *Chaoskube) filterByCooldown(pods []v1.Pod) []v1.Pod {
+	if c.WorkloadCooldown <= 0 && c.NodeCooldown <= 0 {
+		return pods
+	}
+
+	now := c.Now()
+	filteredList := []v1.Pod{}
+
+	for _, pod := range pods {
+		if reason := c.coolingDown(pod, now); reason != "" {
+			c.Logger.Debugf("Excluding pod [%s/%s]: %s", pod.Namespace, pod.Name, reason)
+			continue
+		}
+
+		filteredList = append(filteredList, pod)
+	}
+
+	return filteredList
+}
+
+// coolingDown returns why the given pod's workload or node is cooling down at the given point in
+// time or an empty string.
+func (c *Chaoskube) coolingDown(pod v1.Pod, now time.Time) string {
+	if c.WorkloadCooldown > 0 && c.History.WorkloadCoolingDown(c.Owner(pod), now) {
+		return "workload is cooling down"
+	}
+
+	if c.NodeCooldown > 0 && c.History.NodeCoolingDown(pod.Spec.NodeName, now) {
+		return fmt.Sprintf("node [%s] is cooling down", pod.Spec.NodeName)
+	}
+
+	return ""
+}
@@ -0,0 +1,78 @@
+package chaoskube
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// IntervalFixed waits exactly the interval between runs.
+	IntervalFixed = "fixed"
+	// IntervalJitter waits the interval plus or minus a random percentage of it between runs.
+	IntervalJitter = "jitter"
+	// IntervalExponential waits an exponentially distributed time with the interval as its mean
+	// between runs, so that runs form a Poisson process.
+	IntervalExponential = "exponential"
+
+	// intervalSeedMask is mixed into the seed for victims to derive the seed for intervals
+	intervalSeedMask = 0x5DEECE66D
+)
+
+// IntervalSeed derives the seed for varying intervals from the given seed for picking victims.
+func IntervalSeed(seed int64) int64 {
+	return seed ^ intervalSeedMask
+}
+
+// ValidateInterval checks that the given interval distribution, jitter and bounds make sense.
+func ValidateInterval(distribution string, jitter int, min, max time.Duration) error {
+	switch distribution {
+	case "", IntervalFixed, IntervalExponential:
+	case IntervalJitter:
+		if jitter < 0 || jitter > 100 {
+			return fmt.Errorf("jitter must be between 0 and 100 percent, got %d", jitter)
+		}
+	default:
+		return fmt.Errorf("unknown interval distribution: %s", distribution)
+	}
+
+	if min < 0 || max < 0 {
+		return fmt.Errorf("interval bounds must not be negative")
+	}
+
+	if max > 0 && min > max {
+		return fmt.Errorf("minimum interval %v exceeds maximum interval %v", min, max)
+	}
+
+	return nil
+}
+
+// NextInterval returns how long to wait until the next run according to the interval
+// distribution, bounded by MinInterval and MaxInterval if set. Varied intervals are at least
+// a tenth of the interval if MinInterval isn't set, so that runs never happen back to back.
+func (c *Chaoskube) NextInterval() time.Duration {
+	interval := c.Interval
+	min := c.MinInterval
+
+	switch c.IntervalDistribution {
+	case IntervalJitter:
+		// a random factor in [1-jitter, 1+jitter)
+		factor := 1 + float64(c.IntervalJitter)/100*(2*c.IntervalRand.Float64()-1)
+		interval = time.Duration(float64(c.Interval) * factor)
+	case IntervalExponential:
+		interval = time.Duration(float64(c.Interval) * c.IntervalRand.ExpFloat64())
+	}
+
+	if min == 0 && c.IntervalDistribution != "" && c.IntervalDistribution != IntervalFixed {
+		min = c.Interval / 10
+	}
+
+	if min > 0 && interval < min {
+		interval = min
+	}
+
+	if c.MaxInterval > 0 && interval > c.MaxInterval {
+		interval = c.MaxInterval
+	}
+
+	return interval
+}
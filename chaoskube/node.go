@@ -0,0 +1,52 @@
+package chaoskube
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// filterByNode filters a list of pods by the labels and taints of the nodes they run on.
+// Pods that aren't scheduled yet or whose node can't be looked up are excluded.
+func (c *Chaoskube) filterByNode(pods []v1.Pod) []v1.Pod {
+	// empty filter returns original list
+	if isEmpty(c.NodeLabels) && isEmpty(c.NodeTaints) {
+		return pods
+	}
+
+	filteredList := []v1.Pod{}
+
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			c.Logger.Debugf("Excluding pod [%s/%s]: not scheduled to a node", pod.Namespace, pod.Name)
+			continue
+		}
+
+		node, err := c.node(pod.Spec.NodeName)
+		if err != nil {
+			c.Logger.Debugf("Excluding pod [%s/%s]: failed to look up node [%s]: %v", pod.Namespace, pod.Name, pod.Spec.NodeName, err)
+			continue
+		}
+
+		if !isEmpty(c.NodeLabels) && !c.NodeLabels.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+
+		if !isEmpty(c.NodeTaints) && !c.NodeTaints.Matches(taintSet(node.Spec.Taints)) {
+			continue
+		}
+
+		filteredList = append(filteredList, pod)
+	}
+
+	return filteredList
+}
+
+// taintSet turns a node's taints into a set of labels, so that they can be matched by a
+// selector, e.g. "spot" or "!node-role.kubernetes.io/master". Effects are ignored.
+func taintSet(taints []v1.Taint) labels.Set {
+	set := labels.Set{}
+	for _, taint := range taints {
+		set[taint.Key] = taint.Value
+	}
+	return set
+}
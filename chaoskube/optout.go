@@ -0,0 +1,85 @@
+package chaoskube
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// DisabledAnnotation opts a pod, or all pods of a namespace, out of chaos when set to "true".
+	// On namespaces it may be given as a label, too.
+	DisabledAnnotation = "chaos.disabled"
+	// DisabledUntilAnnotation makes an opt-out lapse at the given time, e.g. 2018-05-04T18:00:00Z.
+	DisabledUntilAnnotation = "chaos.disabled-until"
+)
+
+// filterByOptOut excludes pods that opted out of chaos, either on their own or through their
+// namespace. It overrides all other selectors and fails closed, so that opted out pods are never
+// terminated: it fails if namespaces can't be listed and, without permission to list them, e.g.
+// when running with namespace-scoped Roles only, it excludes all pods with a warning.
+// Namespaces are only listed if any pod didn't opt out on its own.
+func (c *Chaoskube) filterByOptOut(pods []v1.Pod) ([]v1.Pod, error) {
+	now := c.Now()
+	filteredList := []v1.Pod{}
+	forbidden := false
+
+	for _, pod := range pods {
+		if disabled, reason := optedOut(pod.Annotations, pod.Annotations, now); disabled {
+			c.Logger.Debugf("Excluding pod [%s/%s]: pod %s", pod.Namespace, pod.Name, reason)
+			continue
+		}
+
+		namespace, err := c.namespace(pod.Namespace)
+		if apierrors.IsForbidden(err) {
+			if !forbidden {
+				c.Logger.Warnf("Not allowed to list namespaces, excluding all pods as their namespaces may have opted out: %v", err)
+				forbidden = true
+			}
+			continue
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+
+		// a namespace that's gone can't opt out anymore
+		if namespace == nil {
+			namespace = &v1.Namespace{}
+		}
+
+		if disabled, reason := optedOut(namespace.Labels, namespace.Annotations, now); disabled {
+			c.Logger.Debugf("Excluding pod [%s/%s]: namespace %s", pod.Namespace, pod.Name, reason)
+			continue
+		}
+
+		filteredList = append(filteredList, pod)
+	}
+
+	return filteredList, nil
+}
+
+// optedOut returns whether the given labels or annotations disable chaos at the given time and why.
+// An unparsable expiry keeps the opt-out in place.
+func optedOut(labels, annotations map[string]string, now time.Time) (bool, string) {
+	if labels[DisabledAnnotation] != "true" && annotations[DisabledAnnotation] != "true" {
+		return false, ""
+	}
+
+	until, ok := annotations[DisabledUntilAnnotation]
+	if !ok {
+		return true, "opted out"
+	}
+
+	disabledUntil, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return true, fmt.Sprintf("opted out with invalid expiry %q", until)
+	}
+
+	if !now.Before(disabledUntil) {
+		return false, ""
+	}
+
+	return true, fmt.Sprintf("opted out until %s", disabledUntil.Format(time.RFC3339))
+}
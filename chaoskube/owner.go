@@ -0,0 +1,162 @@
+package chaoskube
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Owner identifies the workload that controls a pod.
+type Owner struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// String returns the owner as a pretty string, e.g. Deployment/nginx.
+func (o Owner) String() string {
+	return fmt.Sprintf("%s/%s", o.Kind, o.Name)
+}
+
+// podGroup is a list of pods sharing the same owner.
+type podGroup struct {
+	owner Owner
+	pods  []v1.Pod
+}
+
+// Owner returns the workload controlling the given pod.
+// Pods owned by a ReplicaSet are attributed to the ReplicaSet's Deployment, if any.
+// Pods without a controller are considered their own owner.
+func (c *Chaoskube) Owner(pod v1.Pod) Owner {
+	ref := metav1.GetControllerOf(&pod)
+	if ref == nil {
+		return Owner{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}
+	}
+
+	owner := Owner{Kind: ref.Kind, Namespace: pod.Namespace, Name: ref.Name}
+
+	if ref.Kind == "ReplicaSet" {
+		rs, err := c.workload("ReplicaSet", pod.Namespace, ref.Name)
+		if err != nil {
+			c.Logger.Debugf("Failed to look up ReplicaSet [%s/%s]: %v", pod.Namespace, ref.Name, err)
+			return owner
+		}
+
+		if rsRef := metav1.GetControllerOf(rs.(*appsv1.ReplicaSet)); rsRef != nil && rsRef.Kind == "Deployment" {
+			owner.Kind = rsRef.Kind
+			owner.Name = rsRef.Name
+		}
+	}
+
+	return owner
+}
+
+// desiredReplicas returns the number of replicas the given owner desires.
+// It returns an error if the owner can't be looked up or doesn't have a notion of replicas.
+func (c *Chaoskube) desiredReplicas(owner Owner) (int32, error) {
+	obj, err := c.workload(owner.Kind, owner.Namespace, owner.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		return desiredReplicas(workload.Spec.Replicas), nil
+	case *appsv1.ReplicaSet:
+		return desiredReplicas(workload.Spec.Replicas), nil
+	case *appsv1.StatefulSet:
+		return desiredReplicas(workload.Spec.Replicas), nil
+	case *appsv1.DaemonSet:
+		return workload.Status.DesiredNumberScheduled, nil
+	case *v1.ReplicationController:
+		return desiredReplicas(workload.Spec.Replicas), nil
+	}
+
+	return 0, fmt.Errorf("unsupported workload kind: %s", owner.Kind)
+}
+
+// filterByReplicas filters out pods whose owning workload desires fewer than the configured
+// minimum number of replicas as well as pods without an owner if configured to do so.
+func (c *Chaoskube) filterByReplicas(pods []v1.Pod) []v1.Pod {
+	if c.MinReplicas <= 0 && !c.ExcludeBarePods {
+		return pods
+	}
+
+	filteredList := []v1.Pod{}
+
+	for _, pod := range pods {
+		if metav1.GetControllerOf(&pod) == nil {
+			if c.ExcludeBarePods {
+				c.Logger.Debugf("Excluding pod [%s/%s]: no owner", pod.Namespace, pod.Name)
+				continue
+			}
+			filteredList = append(filteredList, pod)
+			continue
+		}
+
+		if c.MinReplicas > 0 {
+			owner := c.Owner(pod)
+
+			replicas, err := c.desiredReplicas(owner)
+			if err != nil {
+				c.Logger.Debugf("Excluding pod [%s/%s]: failed to determine replicas of %s: %v", pod.Namespace, pod.Name, owner, err)
+				continue
+			}
+
+			if replicas < int32(c.MinReplicas) {
+				c.Logger.Debugf("Excluding pod [%s/%s]: %s has only [%d] replicas", pod.Namespace, pod.Name, owner, replicas)
+				continue
+			}
+		}
+
+		filteredList = append(filteredList, pod)
+	}
+
+	return filteredList
+}
+
+// groupByOwner groups the given pods by their owning workload, preserving the order of the pods.
+func (c *Chaoskube) groupByOwner(pods []v1.Pod) []podGroup {
+	groups := []podGroup{}
+	index := map[Owner]int{}
+
+	for _, pod := range pods {
+		owner := c.Owner(pod)
+
+		i, ok := index[owner]
+		if !ok {
+			i = len(groups)
+			index[owner] = i
+			groups = append(groups, podGroup{owner: owner})
+		}
+
+		groups[i].pods = append(groups[i].pods, pod)
+	}
+
+	return groups
+}
+
+// pickVictimsByOwner picks up to count pods by first picking a random workload and then a pod
+// within it. It never picks more than one pod per workload.
+func (c *Chaoskube) pickVictimsByOwner(pods []v1.Pod, count int) []v1.Pod {
+	groups := c.groupByOwner(pods)
+
+	c.Logger.Debugf("Found [%d] workloads", len(groups))
+
+	victims := []v1.Pod{}
+
+	for len(victims) < count && len(groups) > 0 {
+		i := c.Rand.Intn(len(groups))
+
+		index, err := c.pick(groups[i].pods)
+		if err == nil {
+			victims = append(victims, groups[i].pods[index])
+		}
+
+		groups = append(groups[:i], groups[i+1:]...)
+	}
+
+	return victims
+}
@@ -0,0 +1,172 @@
+package chaoskube
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// errCacheNotSynced is returned when the pod cache couldn't be filled before being stopped
+var errCacheNotSynced = errors.New("failed to sync pod cache")
+
+// listPageSize is the maximum number of pods requested from the API server at once
+const listPageSize = 500
+
+// UsePodCache makes Candidates list pods from a local cache instead of listing all pods from
+// the API server on every run. The cache is kept up to date by informers that watch all pods
+// matching the label selector until stopCh is closed. It blocks until the cache is filled.
+func (c *Chaoskube) UsePodCache(resync time.Duration, stopCh <-chan struct{}) error {
+	listers := podListers{}
+
+	for _, namespace := range c.listNamespaces() {
+		factory := informers.NewFilteredSharedInformerFactory(c.Client, resync, namespace, func(options *metav1.ListOptions) {
+			options.LabelSelector = c.Labels.String()
+		})
+
+		listers[namespace] = factory.Core().V1().Pods().Lister()
+
+		factory.Start(stopCh)
+
+		for _, synced := range factory.WaitForCacheSync(stopCh) {
+			if !synced {
+				return errCacheNotSynced
+			}
+		}
+	}
+
+	c.PodLister = listers
+
+	return nil
+}
+
+// listNamespaces returns the namespaces to list pods from. If the namespace selector includes
+// particular namespaces, only those are listed so that chaoskube works with namespace-scoped
+// permissions. Otherwise pods are listed across all namespaces.
+func (c *Chaoskube) listNamespaces() []string {
+	namespaces := includedNamespaces(c.Namespaces)
+	if len(namespaces) == 0 {
+		return []string{v1.NamespaceAll}
+	}
+	return namespaces
+}
+
+// listPods returns all pods matching the label selector, either from the pod cache or from the
+// API server if no cache is used.
+func (c *Chaoskube) listPods() ([]v1.Pod, error) {
+	if c.PodLister == nil {
+		pods := []v1.Pod{}
+
+		for _, namespace := range c.listNamespaces() {
+			namespacePods, err := c.listPodsInNamespace(namespace)
+			if err != nil {
+				return nil, err
+			}
+			pods = append(pods, namespacePods...)
+		}
+
+		return pods, nil
+	}
+
+	cachedPods, err := c.PodLister.List(c.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make([]v1.Pod, 0, len(cachedPods))
+	for _, pod := range cachedPods {
+		pods = append(pods, *pod)
+	}
+
+	// the cache is unordered, keep the order stable like the API server does
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+
+	return pods, nil
+}
+
+// listPodsInNamespace lists all pods matching the label selector in the given namespace from the
+// API server, fetching them in pages of listPageSize pods.
+func (c *Chaoskube) listPodsInNamespace(namespace string) ([]v1.Pod, error) {
+	pods := []v1.Pod{}
+
+	listOptions := metav1.ListOptions{LabelSelector: c.Labels.String(), Limit: listPageSize}
+
+	for {
+		podList, err := c.Client.CoreV1().Pods(namespace).List(listOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		pods = append(pods, podList.Items...)
+
+		if podList.Continue == "" {
+			return pods, nil
+		}
+		listOptions.Continue = podList.Continue
+	}
+}
+
+// podListers combines the pod listers of several namespaces, keyed by namespace.
+type podListers map[string]corelisters.PodLister
+
+// List lists all pods in all namespaces matching the selector.
+func (l podListers) List(selector labels.Selector) ([]*v1.Pod, error) {
+	pods := []*v1.Pod{}
+
+	for _, lister := range l {
+		namespacePods, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, namespacePods...)
+	}
+
+	return pods, nil
+}
+
+// Pods returns a lister for the pods in the given namespace.
+func (l podListers) Pods(namespace string) corelisters.PodNamespaceLister {
+	if lister, ok := l[namespace]; ok {
+		return lister.Pods(namespace)
+	}
+	if lister, ok := l[v1.NamespaceAll]; ok {
+		return lister.Pods(namespace)
+	}
+	return corelisters.NewPodLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})).Pods(namespace)
+}
+
+// isStale returns true iff the given pod, as seen by the pod cache, no longer exists or is
+// already being deleted. It always returns false if no cache is used.
+func (c *Chaoskube) isStale(pod v1.Pod) bool {
+	if c.PodLister == nil {
+		return false
+	}
+
+	current, err := c.Client.CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true
+	}
+	if err != nil {
+		// let the deletion decide
+		return false
+	}
+
+	// a different pod with the same name, e.g. a recreated StatefulSet pod
+	if current.UID != pod.UID {
+		return true
+	}
+
+	return current.DeletionTimestamp != nil
+}
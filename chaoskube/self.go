@@ -0,0 +1,54 @@
+package chaoskube
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResolveSelf checks that chaoskube's own pod can be looked up, which is required to protect
+// its peers. It returns nil if the own pod is unknown, e.g. when running outside of a cluster.
+func (c *Chaoskube) ResolveSelf() error {
+	if c.SelfNamespace == "" || c.SelfName == "" {
+		return nil
+	}
+
+	_, err := c.Client.CoreV1().Pods(c.SelfNamespace).Get(c.SelfName, metav1.GetOptions{})
+	return err
+}
+
+// filterBySelf excludes chaoskube's own pod and all pods belonging to the same workload, so that
+// chaoskube never terminates itself or its peers.
+func (c *Chaoskube) filterBySelf(pods []v1.Pod) []v1.Pod {
+	// without knowing its own pod there's nothing to protect
+	if c.SelfNamespace == "" || c.SelfName == "" {
+		return pods
+	}
+
+	// the workload chaoskube belongs to, if its own pod can be looked up
+	var self *Owner
+	pod, err := c.Client.CoreV1().Pods(c.SelfNamespace).Get(c.SelfName, metav1.GetOptions{})
+	if err != nil {
+		c.Logger.Warnf("Failed to look up own pod [%s/%s], only protecting the pod itself but not its peers: %v", c.SelfNamespace, c.SelfName, err)
+	} else {
+		owner := c.Owner(*pod)
+		self = &owner
+	}
+
+	filteredList := []v1.Pod{}
+
+	for _, pod := range pods {
+		if pod.Namespace == c.SelfNamespace && pod.Name == c.SelfName {
+			c.Logger.Debugf("Excluding pod [%s/%s]: chaoskube itself", pod.Namespace, pod.Name)
+			continue
+		}
+
+		if self != nil && c.Owner(pod) == *self {
+			c.Logger.Debugf("Excluding pod [%s/%s]: peer of chaoskube owned by %s", pod.Namespace, pod.Name, self)
+			continue
+		}
+
+		filteredList = append(filteredList, pod)
+	}
+
+	return filteredList
+}
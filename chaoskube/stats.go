@@ -0,0 +1,37 @@
+package chaoskube
+
+import (
+	"sync/atomic"
+)
+
+// Stats counts noteworthy events across runs. It is safe for concurrent use, so that it can be
+// read while chaoskube is running and be carried over to a new instance after a config update.
+type Stats struct {
+	blockedEvictions int64
+}
+
+// StatsSnapshot is a point-in-time copy of Stats.
+type StatsSnapshot struct {
+	// the total number of evictions that were blocked by a PodDisruptionBudget
+	BlockedEvictions int64 `json:"blockedEvictions"`
+}
+
+// NewStats returns Stats with all counts at zero.
+func NewStats() *Stats {
+	return &Stats{}
+}
+
+// AddBlockedEvictions adds the given number of evictions blocked by a PodDisruptionBudget.
+func (s *Stats) AddBlockedEvictions(blocked int) {
+	atomic.AddInt64(&s.blockedEvictions, int64(blocked))
+}
+
+// BlockedEvictions returns the total number of evictions blocked by a PodDisruptionBudget.
+func (s *Stats) BlockedEvictions() int64 {
+	return atomic.LoadInt64(&s.blockedEvictions)
+}
+
+// Snapshot returns the current counts.
+func (s *Stats) Snapshot() StatsSnapshot {
+	return StatsSnapshot{BlockedEvictions: s.BlockedEvictions()}
+}
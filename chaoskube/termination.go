@@ -0,0 +1,94 @@
+package chaoskube
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TerminationModeAnnotation is the pod annotation that overrides the configured termination mode.
+const TerminationModeAnnotation = "chaos.termination-mode"
+
+// TerminationMode describes how a victim is terminated.
+// The zero value terminates gracefully using the pod's own terminationGracePeriodSeconds.
+type TerminationMode struct {
+	// the grace period to use instead of the pod's own, nil means the pod's own
+	GracePeriodSeconds *int64
+	// whether to kill the pod without any grace period and not wait for its dependents
+	Immediate bool
+}
+
+// ParseTerminationMode parses a termination mode which is either "graceful", "immediate" or a
+// grace period in whole seconds such as "30s". It ignores any whitespace and treats an empty string as "graceful".
+func ParseTerminationMode(mode string) (TerminationMode, error) {
+	mode = strings.TrimSpace(strings.ToLower(mode))
+
+	switch mode {
+	case "", "graceful":
+		return TerminationMode{}, nil
+	case "immediate":
+		seconds := int64(0)
+		return TerminationMode{GracePeriodSeconds: &seconds, Immediate: true}, nil
+	}
+
+	gracePeriod, err := time.ParseDuration(mode)
+	if err != nil {
+		return TerminationMode{}, fmt.Errorf("invalid termination mode '%v': must be graceful, immediate or a duration", mode)
+	}
+	if gracePeriod < 0 {
+		return TerminationMode{}, fmt.Errorf("invalid termination mode '%v': grace period must not be negative", mode)
+	}
+	// grace periods are given in whole seconds, truncating e.g. 500ms would force-delete the pod
+	if gracePeriod%time.Second != 0 {
+		return TerminationMode{}, fmt.Errorf("invalid termination mode '%v': grace period must be whole seconds", mode)
+	}
+
+	seconds := int64(gracePeriod / time.Second)
+	return TerminationMode{GracePeriodSeconds: &seconds}, nil
+}
+
+// String returns the termination mode as a pretty string.
+func (m TerminationMode) String() string {
+	if m.Immediate {
+		return "immediate"
+	}
+	if m.GracePeriodSeconds == nil {
+		return "graceful"
+	}
+	return (time.Duration(*m.GracePeriodSeconds) * time.Second).String()
+}
+
+// DeleteOptions returns the options to pass along when deleting or evicting a pod.
+func (m TerminationMode) DeleteOptions() *metav1.DeleteOptions {
+	if m.GracePeriodSeconds == nil {
+		return nil
+	}
+
+	options := &metav1.DeleteOptions{GracePeriodSeconds: m.GracePeriodSeconds}
+	if m.Immediate {
+		propagation := metav1.DeletePropagationBackground
+		options.PropagationPolicy = &propagation
+	}
+
+	return options
+}
+
+// terminationModeFor returns the termination mode for the given pod.
+// A valid termination mode annotation on the pod overrides the configured mode.
+func (c *Chaoskube) terminationModeFor(pod v1.Pod) TerminationMode {
+	value, ok := pod.Annotations[TerminationModeAnnotation]
+	if !ok {
+		return c.TerminationMode
+	}
+
+	mode, err := ParseTerminationMode(value)
+	if err != nil {
+		c.Logger.Debugf("Invalid termination mode on pod [%s/%s], using default: %v", pod.Namespace, pod.Name, err)
+		return c.TerminationMode
+	}
+
+	return mode
+}
@@ -3,6 +3,7 @@ package datadog
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/DataDog/datadog-go/statsd"
 	log "github.com/sirupsen/logrus"
@@ -23,8 +24,8 @@ func NewDDClient() (Client *statsd.Client) {
 	return c
 }
 
-// NewEvent ...
-func NewEvent(client *statsd.Client, victim v1.Pod) error {
+// NewEvent sends an event for the killed victim, mentioning its owning workload if not empty
+func NewEvent(client *statsd.Client, victim v1.Pod, owner string) error {
 	var e statsd.Event
 	vertical := os.Getenv("DRP_CF_VERTICAL")
 	stage := os.Getenv("DRP_CF_STAGE")
@@ -36,6 +37,45 @@ func NewEvent(client *statsd.Client, victim v1.Pod) error {
 	e.Text = "Pod " + victim.Name + " was deleted by ChaosKube"
 	e.Priority = "low"
 	e.Tags = []string{"ChaosKube", vertical, stage, location}
+	if owner != "" {
+		e.Text += " (owner: " + owner + ")"
+		e.Tags = append(e.Tags, "owner:"+owner)
+	}
+
+	err := client.Event(&e)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return err
+}
+
+// CountBlockedEvictions reports the given number of evictions blocked by a PodDisruptionBudget
+func CountBlockedEvictions(client *statsd.Client, blocked int) error {
+	vertical := os.Getenv("DRP_CF_VERTICAL")
+	stage := os.Getenv("DRP_CF_STAGE")
+	location := os.Getenv("DRP_CF_LOCATION")
+
+	return client.Count("chaoskube.evictions.blocked", int64(blocked), []string{"ChaosKube", vertical, stage, location}, 1)
+}
+
+// NewDomainEvent sends a single event for all victims killed in the given failure domain
+func NewDomainEvent(client *statsd.Client, failureDomain, domain string, victims []v1.Pod) error {
+	var e statsd.Event
+	vertical := os.Getenv("DRP_CF_VERTICAL")
+	stage := os.Getenv("DRP_CF_STAGE")
+	location := os.Getenv("DRP_CF_LOCATION")
+
+	names := make([]string, 0, len(victims))
+	for _, victim := range victims {
+		names = append(names, victim.Namespace+"/"+victim.Name)
+	}
+
+	e.AlertType = "warning"
+	e.Hostname, _ = os.Hostname()
+	e.Title = fmt.Sprintf("[ChaosKube] %s %s was killed", failureDomain, domain)
+	e.Text = fmt.Sprintf("%d pods in %s %s were deleted by ChaosKube: %s", len(victims), failureDomain, domain, strings.Join(names, ", "))
+	e.Priority = "normal"
+	e.Tags = []string{"ChaosKube", vertical, stage, location, "failure-domain:" + failureDomain, "domain:" + domain}
 
 	err := client.Event(&e)
 	if err != nil {
@@ -1,11 +1,17 @@
 package internal
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
 	"os"
-	"reflect"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
@@ -15,112 +21,363 @@ import (
 	"github.com/metrosystems-cpe/chaoskube/util"
 )
 
+// serviceAccountNamespaceFile holds the namespace of the pod chaoskube runs in
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
 type ChaoskubeConfig struct {
-	Labels             string
-	Annotations        string
-	Namespaces         string
-	ExcludedWeekdays   string
-	ExcludedTimesOfDay string
-	ExcludedDaysOfYear string
-	Timezone           string
-	Master             string
-	Kubeconfig         string
-	DryRun             bool
-	HTTPServer         bool
-	Debug              bool
-	Interval           time.Duration
-	DDEvents           bool
+	Labels                  string
+	Annotations             string
+	Namespaces              string
+	NamespaceLabels         string
+	NamespacePatterns       string
+	PodNamePatterns         string
+	NodeLabels              string
+	NodeTaints              string
+	ExcludedOwnerKinds      string
+	ExcludedPriorityClasses string
+	ExcludedQOSClasses      string
+	PodName                 string
+	PodNamespace            string
+	Seed                    int64
+	FailureDomain           string
+	Schedule                string
+	IntervalDistribution    string
+	IntervalJitter          int
+	MinInterval             time.Duration
+	MaxInterval             time.Duration
+	ExcludedWeekdays        string
+	ExcludedTimesOfDay      string
+	ExcludedDaysOfYear      string
+	Blackouts               string
+	ExcludedWindows         string
+	AllowedWindows          string
+	Timezone                string
+	Master                  string
+	Kubeconfig              string
+	DryRun                  bool
+	HTTPServer              bool
+	Debug                   bool
+	Interval                time.Duration
+	DDEvents                bool
+	WeightAnnotation        string
+	DefaultWeight           int
+	Victims                 int
+	VictimsPercentage       int
+	MaxVictims              int
+	GroupByOwner            bool
+	UseEviction             bool
+	TerminationMode         string
+	MinimumAge              time.Duration
+	PodPhases               string
+	IncludeUnready          bool
+	CheckHealth             bool
+	MinReplicas             int
+	ExcludeBarePods         bool
+	WorkloadCooldown        time.Duration
+	NodeCooldown            time.Duration
+	PodCache                bool
 }
 
-// Diff method used to update config after api call
-func (newConfig *ChaoskubeConfig) Diff(oldConfig *ChaoskubeConfig) *ChaoskubeConfig {
-	v := reflect.ValueOf(*oldConfig)
-	oldConfigStruct := reflect.ValueOf(oldConfig).Elem()
-	newConfigStruct := reflect.ValueOf(newConfig).Elem()
-
-	for i := 0; i < v.NumField(); i++ {
-		fieldName := v.Type().Field(i).Name
-		oldFieldValue := oldConfigStruct.FieldByName(fieldName)
-		newFieldValue := newConfigStruct.FieldByName(fieldName)
-
-		interfaceVal := newFieldValue.Interface()
-		switch tp := newFieldValue.Kind(); tp {
-		case reflect.String:
-			val := interfaceVal.(string)
-			if val != "" && val != oldFieldValue.Interface().(string) {
-				structField := oldConfigStruct.FieldByName(fieldName)
-				structField.Set(newFieldValue)
-			}
-		case reflect.Bool:
-			val := interfaceVal.(bool)
-			if val != oldFieldValue.Interface().(bool) {
-				structField := oldConfigStruct.FieldByName(fieldName)
-				structField.Set(newFieldValue)
-			}
-		default:
-			val := interfaceVal.(time.Duration)
-			if val != oldFieldValue.Interface().(time.Duration) {
-				structField := oldConfigStruct.FieldByName(fieldName)
-				structField.Set(newFieldValue)
-			}
-		}
+// Update returns a copy of the config with the fields of the given JSON document applied.
+// Fields missing from the document keep their current values, so partial updates are safe.
+func (ckFC *ChaoskubeConfig) Update(r io.Reader) (*ChaoskubeConfig, error) {
+	updated := *ckFC
+
+	if err := json.NewDecoder(r).Decode(&updated); err != nil {
+		return nil, err
 	}
 
-	result := oldConfigStruct.Interface().(ChaoskubeConfig)
-	return &result
+	return &updated, nil
 }
 
-func (ckFC *ChaoskubeConfig) NewMonkey() *chaoskube.Chaoskube {
+// parsedConfig holds the values of a ChaoskubeConfig that need parsing.
+type parsedConfig struct {
+	labels             labels.Selector
+	annotations        labels.Selector
+	namespaces         labels.Selector
+	namespaceLabels    labels.Selector
+	nodeLabels         labels.Selector
+	nodeTaints         labels.Selector
+	weekdays           []time.Weekday
+	timesOfDay         []util.TimePeriod
+	daysOfYear         []time.Time
+	blackouts          []util.Blackout
+	excludedWindows    []util.TimeWindow
+	allowedWindows     []util.TimeWindow
+	timezone           *time.Location
+	schedule           *util.Schedule
+	namespacePatterns  []util.NamePattern
+	podNamePatterns    []util.NamePattern
+	excludedQOSClasses []v1.PodQOSClass
+	terminationMode    chaoskube.TerminationMode
+	phases             []v1.PodPhase
+}
 
-	client, err := ckFC.newK8sClient()
-	if err != nil {
-		log.Debugf("Failed to connect to cluster. %v", err)
-	}
+// Validate returns an error if the config contains any invalid value, so that invalid configs
+// can be rejected before they're used.
+func (ckFC *ChaoskubeConfig) Validate() error {
+	_, err := ckFC.parse()
+	return err
+}
 
+// parse parses and validates all values of the config.
+func (ckFC *ChaoskubeConfig) parse() (*parsedConfig, error) {
 	var (
-		labelSelector = parseSelector(ckFC.Labels)
-		annotations   = parseSelector(ckFC.Annotations)
-		namespaces    = parseSelector(ckFC.Namespaces)
+		parsed = &parsedConfig{}
+		err    error
 	)
 
-	log.Infof("Setting pod filters. Labels: [ %v ],  Annotations: [ %v ], Namespaces: [ %v ]", labelSelector, annotations, namespaces)
+	for _, selector := range []struct {
+		name   string
+		value  string
+		target *labels.Selector
+	}{
+		{"labels", ckFC.Labels, &parsed.labels},
+		{"annotations", ckFC.Annotations, &parsed.annotations},
+		{"namespaces", ckFC.Namespaces, &parsed.namespaces},
+		{"namespace labels", ckFC.NamespaceLabels, &parsed.namespaceLabels},
+		{"node labels", ckFC.NodeLabels, &parsed.nodeLabels},
+		{"node taints", ckFC.NodeTaints, &parsed.nodeTaints},
+	} {
+		if *selector.target, err = labels.Parse(selector.value); err != nil {
+			return nil, fmt.Errorf("failed to parse %s. selector: [ %v ], err: %v", selector.name, selector.value, err)
+		}
+	}
+
+	parsed.weekdays = util.ParseWeekdays(ckFC.ExcludedWeekdays)
+	if parsed.timesOfDay, err = util.ParseTimePeriods(ckFC.ExcludedTimesOfDay); err != nil {
+		return nil, fmt.Errorf("failed to parse times of day. timesOfDay: [ %v ], err: %v", ckFC.ExcludedTimesOfDay, err)
+	}
+	if parsed.daysOfYear, err = util.ParseDays(ckFC.ExcludedDaysOfYear); err != nil {
+		return nil, fmt.Errorf("failed to parse days of year. daysOfYear: [ %v ], err: %v", ckFC.ExcludedDaysOfYear, err)
+	}
+	if parsed.blackouts, err = util.ParseBlackouts(ckFC.Blackouts); err != nil {
+		return nil, fmt.Errorf("failed to parse blackouts. blackouts: [ %v ], err: %v", ckFC.Blackouts, err)
+	}
+	if parsed.excludedWindows, err = util.ParseWeekdayTimePeriods(ckFC.ExcludedWindows); err != nil {
+		return nil, fmt.Errorf("failed to parse excluded windows. windows: [ %v ], err: %v", ckFC.ExcludedWindows, err)
+	}
+	if parsed.allowedWindows, err = util.ParseTimeWindows(ckFC.AllowedWindows); err != nil {
+		return nil, fmt.Errorf("failed to parse allowed windows. windows: [ %v ], err: %v", ckFC.AllowedWindows, err)
+	}
+	if parsed.timezone, err = time.LoadLocation(ckFC.Timezone); err != nil {
+		return nil, fmt.Errorf("failed to detect time zone. tz: [ %v ], err: %v", ckFC.Timezone, err)
+	}
+
+	if err := chaoskube.ValidateInterval(ckFC.IntervalDistribution, ckFC.IntervalJitter, ckFC.MinInterval, ckFC.MaxInterval); err != nil {
+		return nil, fmt.Errorf("invalid interval. distribution: [ %v ], err: %v", ckFC.IntervalDistribution, err)
+	}
 
-	parsedWeekdays := util.ParseWeekdays(ckFC.ExcludedWeekdays)
-	parsedTimesOfDay, err := util.ParseTimePeriods(ckFC.ExcludedTimesOfDay)
+	if ckFC.Schedule != "" {
+		if parsed.schedule, err = util.ParseSchedule(ckFC.Schedule); err != nil {
+			return nil, fmt.Errorf("failed to parse schedule. schedule: [ %v ], err: %v", ckFC.Schedule, err)
+		}
+		if parsed.schedule.Next(time.Now().In(parsed.timezone)).IsZero() {
+			return nil, fmt.Errorf("schedule never runs. schedule: [ %v ]", ckFC.Schedule)
+		}
+	}
+
+	if parsed.namespacePatterns, err = util.ParseNamePatterns(ckFC.NamespacePatterns); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace patterns. patterns: [ %v ], err: %v", ckFC.NamespacePatterns, err)
+	}
+	if parsed.podNamePatterns, err = util.ParseNamePatterns(ckFC.PodNamePatterns); err != nil {
+		return nil, fmt.Errorf("failed to parse pod name patterns. patterns: [ %v ], err: %v", ckFC.PodNamePatterns, err)
+	}
+	if parsed.excludedQOSClasses, err = util.ParseQOSClasses(ckFC.ExcludedQOSClasses); err != nil {
+		return nil, fmt.Errorf("failed to parse QoS classes. classes: [ %v ], err: %v", ckFC.ExcludedQOSClasses, err)
+	}
+
+	if ckFC.DefaultWeight < 0 {
+		return nil, fmt.Errorf("invalid default weight. weight: [ %d ], err: must not be negative", ckFC.DefaultWeight)
+	}
+
+	if ckFC.Victims < 1 {
+		return nil, fmt.Errorf("invalid victims. victims: [ %d ], err: must be at least 1", ckFC.Victims)
+	}
+	if ckFC.VictimsPercentage < 0 || ckFC.VictimsPercentage > 100 {
+		return nil, fmt.Errorf("invalid victims percentage. percentage: [ %d ], err: must be between 0 and 100", ckFC.VictimsPercentage)
+	}
+	if ckFC.MaxVictims < 0 {
+		return nil, fmt.Errorf("invalid max victims. max: [ %d ], err: must not be negative", ckFC.MaxVictims)
+	}
+
+	if parsed.terminationMode, err = chaoskube.ParseTerminationMode(ckFC.TerminationMode); err != nil {
+		return nil, fmt.Errorf("failed to parse termination mode. mode: [ %v ], err: %v", ckFC.TerminationMode, err)
+	}
+	if parsed.phases, err = util.ParsePodPhases(ckFC.PodPhases); err != nil {
+		return nil, fmt.Errorf("failed to parse pod phases. phases: [ %v ], err: %v", ckFC.PodPhases, err)
+	}
+
+	return parsed, nil
+}
+
+func (ckFC *ChaoskubeConfig) NewMonkey() *chaoskube.Chaoskube {
+	parsed, err := ckFC.parse()
 	if err != nil {
-		log.Fatalf("failed to parse times of day. timesOfDay: [ %v ], err: %v", ckFC.ExcludedTimesOfDay, err)
+		log.Fatal(err)
 	}
-	parsedDaysOfYear, err := util.ParseDays(ckFC.ExcludedDaysOfYear)
+
+	client, err := ckFC.newK8sClient()
 	if err != nil {
-		log.Fatalf("failed to parse days of year. daysOfYear: [ %v ], err: %v", ckFC.ExcludedDaysOfYear, err)
+		log.Debugf("Failed to connect to cluster. %v", err)
 	}
 
-	log.Infof("Setting quiet times... Weeks: %v, timesOfDay: %v, daysOfYear: %v", parsedWeekdays, parsedTimesOfDay, formatDays(parsedDaysOfYear))
+	log.Infof("Setting pod filters. Labels: [ %v ],  Annotations: [ %v ], Namespaces: [ %v ], NamespaceLabels: [ %v ], MinimumAge: [ %v ]", parsed.labels, parsed.annotations, parsed.namespaces, parsed.namespaceLabels, ckFC.MinimumAge)
 
-	parsedTimezone, err := time.LoadLocation(ckFC.Timezone)
-	if err != nil {
-		log.Fatalf("Failed to detect time zone. tz: %v, err: %v", ckFC.Timezone, err)
+	log.Infof("Setting quiet times... Weeks: %v, timesOfDay: %v, daysOfYear: %v", parsed.weekdays, parsed.timesOfDay, formatDays(parsed.daysOfYear))
+
+	if len(parsed.blackouts) > 0 {
+		log.Infof("Setting blackouts: %v", parsed.blackouts)
+	}
+	if len(parsed.excludedWindows) > 0 {
+		log.Infof("Setting excluded windows: %v", parsed.excludedWindows)
 	}
-	timezoneName, offset := time.Now().In(parsedTimezone).Zone()
-	log.Infof("Setting timezone to: name: %s, location: %s, offset: %d", timezoneName, parsedTimezone, offset/int(time.Hour/time.Second))
+	if len(parsed.allowedWindows) > 0 {
+		log.Infof("Setting allowed windows: %v", parsed.allowedWindows)
+	}
+
+	timezoneName, offset := time.Now().In(parsed.timezone).Zone()
+	log.Infof("Setting timezone to: name: %s, location: %s, offset: %d", timezoneName, parsed.timezone, offset/int(time.Hour/time.Second))
 
 	ck := chaoskube.New(
 		client,
-		labelSelector,
-		annotations,
-		namespaces,
-		parsedWeekdays,
-		parsedTimesOfDay,
-		parsedDaysOfYear,
-		parsedTimezone,
+		parsed.labels,
+		parsed.annotations,
+		parsed.namespaces,
+		parsed.weekdays,
+		parsed.timesOfDay,
+		parsed.daysOfYear,
+		parsed.timezone,
 		log.StandardLogger(),
 		ckFC.DryRun,
 		ckFC.DDEvents,
 		datadog.NewDDClient(),
 	)
+
+	log.Infof("Setting interval: %v, distribution: %v, jitter: %d%%, bounds: [ %v, %v ]", ckFC.Interval, ckFC.IntervalDistribution, ckFC.IntervalJitter, ckFC.MinInterval, ckFC.MaxInterval)
+	ck.Interval = ckFC.Interval
+	ck.IntervalDistribution = ckFC.IntervalDistribution
+	ck.IntervalJitter = ckFC.IntervalJitter
+	ck.MinInterval = ckFC.MinInterval
+	ck.MaxInterval = ckFC.MaxInterval
+
+	if parsed.schedule != nil {
+		ck.Schedule = parsed.schedule
+		log.Infof("Setting schedule: [ %v ], next run: %v", ck.Schedule, ck.NextRun())
+	}
+
+	if ckFC.FailureDomain != "" {
+		log.Infof("Terminating whole failure domains. Domain: [ %v ], max victims: %d", ckFC.FailureDomain, ckFC.MaxVictims)
+	}
+	ck.FailureDomain = ckFC.FailureDomain
+
+	log.Infof("Using random seed: %d", ckFC.Seed)
+	ck.Rand = rand.New(rand.NewSource(ckFC.Seed))
+	ck.IntervalRand = rand.New(rand.NewSource(chaoskube.IntervalSeed(ckFC.Seed)))
+
+	ck.NamespaceLabels = parsed.namespaceLabels
+	ck.Blackouts = parsed.blackouts
+	ck.ExcludedWindows = parsed.excludedWindows
+	ck.AllowedWindows = parsed.allowedWindows
+
+	if len(parsed.namespacePatterns) > 0 || len(parsed.podNamePatterns) > 0 {
+		log.Infof("Setting name patterns. Namespaces: %v, pods: %v", parsed.namespacePatterns, parsed.podNamePatterns)
+	}
+	ck.NamespacePatterns = parsed.namespacePatterns
+	ck.PodNamePatterns = parsed.podNamePatterns
+
+	ck.NodeLabels = parsed.nodeLabels
+	ck.NodeTaints = parsed.nodeTaints
+	if !ck.NodeLabels.Empty() || !ck.NodeTaints.Empty() {
+		log.Infof("Setting node filters. Labels: [ %v ], taints: [ %v ]", ck.NodeLabels, ck.NodeTaints)
+	}
+
+	ck.ExcludedOwnerKinds = util.ParseList(ckFC.ExcludedOwnerKinds)
+	ck.ExcludedPriorityClasses = util.ParseList(ckFC.ExcludedPriorityClasses)
+	ck.ExcludedQOSClasses = parsed.excludedQOSClasses
+	ck.SelfNamespace, ck.SelfName = ckFC.selfPod()
+	if ck.SelfName != "" {
+		if client != nil {
+			if err := ck.ResolveSelf(); err != nil {
+				log.Fatalf("failed to look up own pod, set POD_NAME and POD_NAMESPACE via the downward API. pod: [ %s/%s ], err: %v", ck.SelfNamespace, ck.SelfName, err)
+			}
+		}
+		log.Infof("Protecting own pod and its peers. Pod: [ %s/%s ]", ck.SelfNamespace, ck.SelfName)
+	} else {
+		log.Info("Own pod unknown, not protecting it")
+	}
+
+	log.Infof("Setting excluded categories. Owner kinds: %v, priority classes: %v, QoS classes: %v", ck.ExcludedOwnerKinds, ck.ExcludedPriorityClasses, ck.ExcludedQOSClasses)
+
+	if ckFC.WeightAnnotation != "" {
+		log.Infof("Setting victim weights. Annotation: [ %v ], default weight: %d", ckFC.WeightAnnotation, ckFC.DefaultWeight)
+	}
+	ck.WeightAnnotation = ckFC.WeightAnnotation
+	ck.DefaultWeight = ckFC.DefaultWeight
+
+	log.Infof("Setting victims per run. Count: %d, percentage: %d, max: %d", ckFC.Victims, ckFC.VictimsPercentage, ckFC.MaxVictims)
+	ck.VictimCount = ckFC.Victims
+	ck.VictimPercentage = ckFC.VictimsPercentage
+	ck.MaxVictims = ckFC.MaxVictims
+
+	if ckFC.GroupByOwner {
+		log.Info("Picking victims by owning workload")
+	}
+	ck.GroupByOwner = ckFC.GroupByOwner
+
+	if ckFC.UseEviction {
+		log.Info("Terminating pods through the Eviction API")
+	}
+	ck.UseEviction = ckFC.UseEviction
+
+	log.Infof("Setting termination mode: %v", parsed.terminationMode)
+	ck.TerminationMode = parsed.terminationMode
+	ck.MinimumAge = ckFC.MinimumAge
+
+	log.Infof("Setting pod status filter. Phases: %v, includeUnready: %v", parsed.phases, ckFC.IncludeUnready)
+	ck.Phases = parsed.phases
+	ck.IncludeUnready = ckFC.IncludeUnready
+
+	if ckFC.CheckHealth {
+		log.Info("Sparing pods of degraded workloads")
+	}
+	ck.CheckWorkloadHealth = ckFC.CheckHealth
+
+	log.Infof("Setting workload filter. MinReplicas: %d, excludeBarePods: %v", ckFC.MinReplicas, ckFC.ExcludeBarePods)
+	ck.MinReplicas = ckFC.MinReplicas
+	ck.ExcludeBarePods = ckFC.ExcludeBarePods
+
+	log.Infof("Setting cooldowns. Workload: %v, node: %v", ckFC.WorkloadCooldown, ckFC.NodeCooldown)
+	ck.WorkloadCooldown = ckFC.WorkloadCooldown
+	ck.NodeCooldown = ckFC.NodeCooldown
+
 	return ck
 }
 
+// selfPod returns the namespace and name of chaoskube's own pod as given by the downward API.
+// It falls back to the pod's hostname and service account namespace when running in a cluster.
+func (ckFC *ChaoskubeConfig) selfPod() (string, string) {
+	namespace, name := ckFC.PodNamespace, ckFC.PodName
+
+	if namespace == "" {
+		if data, err := ioutil.ReadFile(serviceAccountNamespaceFile); err == nil {
+			namespace = strings.TrimSpace(string(data))
+		}
+	}
+
+	if name == "" && namespace != "" {
+		if hostname, err := os.Hostname(); err == nil {
+			name = hostname
+		}
+	}
+
+	if namespace == "" || name == "" {
+		return "", ""
+	}
+
+	return namespace, name
+}
+
 // newK8sClient returns a new kubernetes client
 func (ckFC *ChaoskubeConfig) newK8sClient() (*kubernetes.Clientset, error) {
 	if ckFC.Kubeconfig == "" {
@@ -149,14 +406,6 @@ func (ckFC *ChaoskubeConfig) newK8sClient() (*kubernetes.Clientset, error) {
 	return client, nil
 }
 
-func parseSelector(str string) labels.Selector {
-	selector, err := labels.Parse(str)
-	if err != nil {
-		log.Fatal("failed to parse selector")
-	}
-	return selector
-}
-
 func formatDays(days []time.Time) []string {
 	formattedDays := make([]string, 0, len(days))
 	for _, d := range days {
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type Suite struct {
+	suite.Suite
+}
+
+// TestUpdate tests that a partial update only changes the given fields and keeps all others
+func (suite *Suite) TestUpdate() {
+	config := &ChaoskubeConfig{
+		Labels:           "app=foo",
+		Interval:         10 * time.Minute,
+		WorkloadCooldown: time.Hour,
+		NodeCooldown:     30 * time.Minute,
+		MinimumAge:       5 * time.Minute,
+		CheckHealth:      true,
+		UseEviction:      true,
+		ExcludeBarePods:  true,
+		DefaultWeight:    1,
+	}
+
+	updated, err := config.Update(strings.NewReader(`{"Labels": "app=bar", "DryRun": true}`))
+	suite.Require().NoError(err)
+
+	expected := *config
+	expected.Labels = "app=bar"
+	expected.DryRun = true
+	suite.Equal(&expected, updated)
+
+	// the current config is left alone
+	suite.Equal("app=foo", config.Labels)
+	suite.False(config.DryRun)
+
+	// fields can be reset explicitly
+	updated, err = config.Update(strings.NewReader(`{"CheckHealth": false, "WorkloadCooldown": 0}`))
+	suite.Require().NoError(err)
+	suite.False(updated.CheckHealth)
+	suite.Equal(time.Duration(0), updated.WorkloadCooldown)
+	suite.Equal(30*time.Minute, updated.NodeCooldown)
+
+	_, err = config.Update(strings.NewReader(`{"CheckHealth": "yes"}`))
+	suite.Error(err)
+}
+
+// TestValidate tests that invalid values are rejected before a config is used
+func (suite *Suite) TestValidate() {
+	valid := ChaoskubeConfig{
+		Labels:          "app=foo",
+		Timezone:        "UTC",
+		Interval:        10 * time.Minute,
+		TerminationMode: "graceful",
+		PodPhases:       "Running",
+		Schedule:        "*/15 9-16 * * Mon-Fri",
+		Victims:         1,
+	}
+	suite.NoError(valid.Validate())
+
+	for _, tt := range []struct {
+		name   string
+		modify func(*ChaoskubeConfig)
+	}{
+		{"labels", func(c *ChaoskubeConfig) { c.Labels = "app in (foo" }},
+		{"namespace patterns", func(c *ChaoskubeConfig) { c.NamespacePatterns = "^(" }},
+		{"pod name patterns", func(c *ChaoskubeConfig) { c.PodNamePatterns = "^[" }},
+		{"termination mode", func(c *ChaoskubeConfig) { c.TerminationMode = "forceful" }},
+		{"pod phases", func(c *ChaoskubeConfig) { c.PodPhases = "Sleeping" }},
+		{"QoS classes", func(c *ChaoskubeConfig) { c.ExcludedQOSClasses = "Platinum" }},
+		{"schedule", func(c *ChaoskubeConfig) { c.Schedule = "* * *" }},
+		{"schedule never runs", func(c *ChaoskubeConfig) { c.Schedule = "0 0 30 Feb *" }},
+		{"allowed windows", func(c *ChaoskubeConfig) { c.AllowedWindows = "Funday 10:00-16:00" }},
+		{"excluded windows", func(c *ChaoskubeConfig) { c.ExcludedWindows = "10:00-16:00" }},
+		{"blackouts", func(c *ChaoskubeConfig) { c.Blackouts = "Dec32" }},
+		{"timezone", func(c *ChaoskubeConfig) { c.Timezone = "Nowhere/Special" }},
+		{"interval", func(c *ChaoskubeConfig) { c.IntervalDistribution = "gaussian" }},
+		{"default weight", func(c *ChaoskubeConfig) { c.DefaultWeight = -1 }},
+		{"no victims", func(c *ChaoskubeConfig) { c.Victims = 0 }},
+		{"negative victims percentage", func(c *ChaoskubeConfig) { c.VictimsPercentage = -10 }},
+		{"victims percentage above 100", func(c *ChaoskubeConfig) { c.VictimsPercentage = 250 }},
+		{"negative max victims", func(c *ChaoskubeConfig) { c.MaxVictims = -1 }},
+	} {
+		config := valid
+		tt.modify(&config)
+
+		suite.Error(config.Validate(), tt.name)
+	}
+}
+
+func TestSuite(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
@@ -36,8 +36,8 @@ type customLogFields struct {
 
 var hostName = ""
 
-// WithCustomFields adds more details to log lines
-func WithCustomFields(victim string) *logrus.Entry {
+// WithCustomFields adds more details to log lines written to the given logger
+func WithCustomFields(logger logrus.FieldLogger, victim string) *logrus.Entry {
 	custom := customLogFields{}
 	splitted := strings.Split(victim, "-")
 	if len(splitted) != 4 {
@@ -49,7 +49,7 @@ func WithCustomFields(victim string) *logrus.Entry {
 		custom.ChaosAction = "KILL"
 	}
 
-	return logrus.WithFields(*logrusFields(custom))
+	return logger.WithFields(*logrusFields(custom))
 }
 
 func init() {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"math/rand"
 	"net/http"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -11,6 +12,7 @@ import (
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"github.com/metrosystems-cpe/chaoskube/chaoskube"
 	"github.com/metrosystems-cpe/chaoskube/internal"
 )
 
@@ -18,31 +20,78 @@ var (
 	version = "undefined"
 	ckConf  = &internal.ChaoskubeConfig{}
 	quit    = make(chan bool) // channel used to send "kill" message to routine where monkey run.
+
+	monkey      *chaoskube.Chaoskube // the currently running monkey, used by the http handlers
+	monkeyMutex sync.RWMutex
+
+	seedSet bool // whether --seed was given, as zero is a valid seed
 )
 
 func init() {
-	rand.Seed(time.Now().UTC().UnixNano())
-
 	kingpin.Flag("labels", "A set of labels to restrict the list of affected pods. Defaults to everything.").StringVar(&ckConf.Labels)
 	kingpin.Flag("annotations", "A set of annotations to restrict the list of affected pods. Defaults to everything.").StringVar(&ckConf.Annotations)
 	kingpin.Flag("namespaces", "A set of namespaces to restrict the list of affected pods. Defaults to everything.").StringVar(&ckConf.Namespaces)
+	kingpin.Flag("namespace-labels", "A set of labels to restrict the list of affected pods to namespaces labeled accordingly, e.g. team=payments,chaos=enabled. Defaults to everything.").StringVar(&ckConf.NamespaceLabels)
+	kingpin.Flag("namespace-patterns", "A set of glob or regexp (starting with ^) patterns on namespace names to restrict the list of affected pods, prefix with ! to exclude, e.g. team-*-staging,!*-prod. Defaults to everything.").StringVar(&ckConf.NamespacePatterns)
+	kingpin.Flag("pod-name-patterns", "A set of glob or regexp (starting with ^) patterns on pod names to restrict the list of affected pods, prefix with ! to exclude, e.g. ^batch-.*. Defaults to everything.").StringVar(&ckConf.PodNamePatterns)
+	kingpin.Flag("node-labels", "A set of labels to restrict the list of affected pods to pods running on nodes labeled accordingly, e.g. pool=spot. Defaults to everything.").StringVar(&ckConf.NodeLabels)
+	kingpin.Flag("node-taints", "A selector on taint keys and values to restrict the list of affected pods by the taints of their nodes, e.g. !node-role.kubernetes.io/master. Defaults to everything.").StringVar(&ckConf.NodeTaints)
+	kingpin.Flag("excluded-owner-kinds", "A list of controller kinds whose pods are never terminated, e.g. DaemonSet,Job.").StringVar(&ckConf.ExcludedOwnerKinds)
+	kingpin.Flag("excluded-priority-classes", "A list of priority class names whose pods are never terminated, e.g. system-cluster-critical.").StringVar(&ckConf.ExcludedPriorityClasses)
+	kingpin.Flag("excluded-qos-classes", "A list of QoS classes whose pods are never terminated, e.g. Guaranteed.").StringVar(&ckConf.ExcludedQOSClasses)
+	kingpin.Flag("pod-name", "The name of chaoskube's own pod, which is never terminated along with its peers. Defaults to the hostname when running in a cluster.").Envar("POD_NAME").StringVar(&ckConf.PodName)
+	kingpin.Flag("pod-namespace", "The namespace of chaoskube's own pod. Defaults to the service account's namespace when running in a cluster.").Envar("POD_NAMESPACE").StringVar(&ckConf.PodNamespace)
+	kingpin.Flag("minimum-age", "Minimum time a pod must be running before it can be terminated, e.g. 1h. Pods can override it with the chaos.minimum-age annotation.").Default("0s").DurationVar(&ckConf.MinimumAge)
+	kingpin.Flag("pod-phases", "A list of pod phases to restrict the list of affected pods, e.g. Running,Pending. An empty list allows any phase.").Default("Running").StringVar(&ckConf.PodPhases)
+	kingpin.Flag("include-unready", "Also target pods that aren't ready.").BoolVar(&ckConf.IncludeUnready)
+	kingpin.Flag("min-replicas", "Only target pods whose owning workload desires at least this many replicas. Defaults to no limit.").Default("0").IntVar(&ckConf.MinReplicas)
+	kingpin.Flag("exclude-bare-pods", "Don't target pods that aren't controlled by any workload.").BoolVar(&ckConf.ExcludeBarePods)
+	kingpin.Flag("workload-cooldown", "Time during which a workload is spared after one of its pods was terminated, e.g. 1h.").Default("0s").DurationVar(&ckConf.WorkloadCooldown)
+	kingpin.Flag("node-cooldown", "Time during which the pods on a node are spared after one of them was terminated, e.g. 30m.").Default("0s").DurationVar(&ckConf.NodeCooldown)
+	kingpin.Flag("pod-cache", "If true, watch pods and pick candidates from a local cache instead of listing all pods every interval.").BoolVar(&ckConf.PodCache)
 	kingpin.Flag("excluded-weekdays", "A list of weekdays when termination is suspended, e.g. Sat,Sun").StringVar(&ckConf.ExcludedWeekdays)
 	kingpin.Flag("excluded-times-of-day", "A list of time periods of a day when termination is suspended, e.g. 22:00-08:00").StringVar(&ckConf.ExcludedTimesOfDay)
 	kingpin.Flag("excluded-days-of-year", "A list of days of a year when termination is suspended, e.g. Apr1,Dec24").StringVar(&ckConf.ExcludedDaysOfYear)
+	kingpin.Flag("blackouts", "A list of optionally named ranges of days when termination is suspended, e.g. freeze=2026-12-18..2027-01-04,Dec20..Jan5").StringVar(&ckConf.Blackouts)
+	kingpin.Flag("excluded-windows", "A list of weekday-specific time periods separated by ; when termination is suspended, e.g. Fri 14:00-23:59;Mon 00:00-10:00").StringVar(&ckConf.ExcludedWindows)
+	kingpin.Flag("allowed-windows", "A list of time windows separated by ; outside of which termination is suspended, e.g. Mon-Fri 10:00-16:00").StringVar(&ckConf.AllowedWindows)
 	kingpin.Flag("timezone", "The timezone by which to interpret the excluded weekdays and times of day, e.g. UTC, Local, Europe/Berlin. Defaults to UTC.").Default("UTC").StringVar(&ckConf.Timezone)
 	kingpin.Flag("master", "The address of the Kubernetes cluster to target").StringVar(&ckConf.Master)
 	kingpin.Flag("kubeconfig", "Path to a kubeconfig file").StringVar(&ckConf.Kubeconfig)
 	kingpin.Flag("interval", "Interval between Pod terminations").Default("10m").DurationVar(&ckConf.Interval)
+	kingpin.Flag("interval-distribution", "How to vary the time between pod terminations around --interval: fixed, jitter or exponential.").Default("fixed").EnumVar(&ckConf.IntervalDistribution, "fixed", "jitter", "exponential")
+	kingpin.Flag("interval-jitter", "The maximum deviation from --interval in percent when using the jitter distribution, e.g. 20.").Default("0").IntVar(&ckConf.IntervalJitter)
+	kingpin.Flag("min-interval", "The minimum time between pod terminations when varying the interval. Defaults to a tenth of --interval.").Default("0s").DurationVar(&ckConf.MinInterval)
+	kingpin.Flag("max-interval", "The maximum time between pod terminations when varying the interval. Defaults to no limit.").Default("0s").DurationVar(&ckConf.MaxInterval)
+	kingpin.Flag("schedule", "A cron expression on which to terminate pods instead of a fixed interval, evaluated in --timezone, e.g. \"*/15 9-16 * * Mon-Fri\".").StringVar(&ckConf.Schedule)
 	kingpin.Flag("dry-run", "If true, don't actually do anything.").Default("true").BoolVar(&ckConf.DryRun)
+	kingpin.Flag("failure-domain", "Terminate all candidates of a random failure domain at once instead of individual pods, capped by --max-victims. Either node or a node label such as topology.kubernetes.io/zone.").StringVar(&ckConf.FailureDomain)
+	kingpin.Flag("seed", "Seed for picking victims, the same seed reproduces the same sequence of victims given the same candidates. Defaults to a random seed.").Action(func(*kingpin.ParseContext) error {
+		seedSet = true
+		return nil
+	}).Int64Var(&ckConf.Seed)
 	kingpin.Flag("debug", "Enable debug logging.").BoolVar(&ckConf.Debug)
 	kingpin.Flag("httpServer", "Enable httpServer.").Default("true").BoolVar(&ckConf.HTTPServer)
 	kingpin.Flag("DDEvents", "toggle data dog events").Default("true").BoolVar(&ckConf.DDEvents)
+	kingpin.Flag("weight-annotation", "An annotation holding each pod's relative odds of being killed, e.g. chaos.weight. Defaults to equal odds.").StringVar(&ckConf.WeightAnnotation)
+	kingpin.Flag("default-weight", "The weight of pods without the weight annotation").Default("1").IntVar(&ckConf.DefaultWeight)
+	kingpin.Flag("victims", "The number of distinct pods to terminate per interval").Default("1").IntVar(&ckConf.Victims)
+	kingpin.Flag("victims-percentage", "The percentage of candidates to terminate per interval, overrides --victims if set").Default("0").IntVar(&ckConf.VictimsPercentage)
+	kingpin.Flag("group-by-owner", "Pick a workload (Deployment, StatefulSet, DaemonSet, ...) first and then a pod within it. Kills at most one pod per workload per interval.").BoolVar(&ckConf.GroupByOwner)
+	kingpin.Flag("check-health", "Spare pods whose Deployment, StatefulSet or ReplicaSet is degraded or in the middle of a rollout.").BoolVar(&ckConf.CheckHealth)
+	kingpin.Flag("use-eviction", "Evict pods through the Eviction API instead of deleting them, so PodDisruptionBudgets are respected.").BoolVar(&ckConf.UseEviction)
+	kingpin.Flag("termination-mode", "How to terminate pods: graceful (the pod's own grace period), immediate (no grace period) or a fixed grace period, e.g. 5s. Pods can override it with the chaos.termination-mode annotation.").Default("graceful").StringVar(&ckConf.TerminationMode)
+	kingpin.Flag("max-victims", "The maximum number of pods to terminate per interval. Defaults to no limit.").Default("0").IntVar(&ckConf.MaxVictims)
 }
 
 func main() {
 	kingpin.Version(version)
 	kingpin.Parse()
 
+	if !seedSet {
+		ckConf.Seed = rand.New(rand.NewSource(time.Now().UnixNano())).Int63()
+	}
+
 	if ckConf.Debug {
 		log.SetLevel(log.DebugLevel)
 	}
@@ -59,6 +108,8 @@ func httpMuxServer() {
 	mux.HandleFunc("/.well-known/live", healthHandler)    // k8s pod process started
 	mux.HandleFunc("/.well-known/ready", healthHandler)   // k8s pod is ready to accept traffic
 	mux.HandleFunc("/api/v1/update", updateConfigHandler) // k8s pod is ready to accept traffic
+	mux.HandleFunc("/api/v1/cooldowns", cooldownsHandler) // workloads and nodes currently spared
+	mux.HandleFunc("/api/v1/stats", statsHandler)         // counts such as blocked evictions
 
 	// log.WithFields("info", "http server").Info("http server started on :8080")
 	log.Infoln("http server started on :8080")
@@ -69,18 +120,21 @@ func httpMuxServer() {
 }
 
 func updateConfigHandler(wr http.ResponseWriter, req *http.Request) {
-	newConf := internal.ChaoskubeConfig{}
-	decoder := json.NewDecoder(req.Body)
-	err := decoder.Decode(&newConf)
-	// TODO: Need to find a way to validate config :-?
+	newConf, err := ckConf.Update(req.Body)
 
 	if err != nil {
 		log.Infof("Fail to decode params. Error: %v", err)
 		wr.WriteHeader(http.StatusInternalServerError)
 		wr.Write([]byte(`{"Status": "Something went wrong. Check logs..."}`)) // Need better error message
+	} else if err := newConf.Validate(); err != nil {
+		// reject invalid configs right away instead of crashing when the new monkey starts
+		log.Infof("Rejecting invalid config. Error: %v", err)
+		status, _ := json.Marshal(map[string]string{"Status": err.Error()})
+		wr.WriteHeader(http.StatusBadRequest)
+		wr.Write(status)
 	} else {
 		log.Info("Config updated and will be used after monkey finishes sleep.")
-		ckConf = newConf.Diff(ckConf)
+		ckConf = newConf
 		go func() {
 			// kill old monkey by pushing true on quit channel
 			quit <- true
@@ -95,21 +149,49 @@ func updateConfigHandler(wr http.ResponseWriter, req *http.Request) {
 }
 
 func startMonkey() {
-	log.Infof("Start Monkey! dryRun: %v, Interval: %v", ckConf.DryRun, ckConf.Interval)
+	log.Infof("Start Monkey! dryRun: %v, Interval: %v, Schedule: %v", ckConf.DryRun, ckConf.Interval, ckConf.Schedule)
 
-	monkey := ckConf.NewMonkey()
+	m := ckConf.NewMonkey()
+
+	monkeyMutex.Lock()
+	if monkey != nil {
+		// keep remembering recent victims and counting events across config updates
+		m.History = monkey.History
+		m.Stats = monkey.Stats
+	}
+	monkey = m
+	monkeyMutex.Unlock()
+
+	if ckConf.PodCache {
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+
+		log.Info("Watching pods to fill the pod cache")
+		if err := m.UsePodCache(0, stopCh); err != nil {
+			log.Fatalf("Failed to set up pod cache: %v", err)
+		}
+	}
 
 	for {
 		select {
 		case <-quit:
 			return
 		default:
-			if err := monkey.TerminateVictim(); err != nil {
+			if m.Schedule != nil {
+				next := m.NextRun()
+				log.Debugf("Sleeping until %v", next)
+				time.Sleep(time.Until(next))
+			}
+
+			if err := m.TerminateVictim(); err != nil {
 				log.Errorf("Failed to terminate victim: %v", err)
 			}
 
-			log.Debugf("Sleeping for %v", ckConf.Interval)
-			time.Sleep(ckConf.Interval)
+			if m.Schedule == nil {
+				interval := m.NextInterval()
+				log.Debugf("Sleeping for %v", interval)
+				time.Sleep(interval)
+			}
 		}
 	}
 }
@@ -133,3 +215,47 @@ func configHandler(wr http.ResponseWriter, req *http.Request) {
 	wr.Header().Set("Content-Type", "application/json")
 	wr.Write(configData)
 }
+
+// cooldownsHandler lists the workloads and nodes that are currently spared due to a cooldown
+func cooldownsHandler(wr http.ResponseWriter, req *http.Request) {
+	wr.Header().Set("Access-Control-Allow-Origin", "*")
+
+	monkeyMutex.RLock()
+	m := monkey
+	monkeyMutex.RUnlock()
+
+	cooldowns := []chaoskube.Cooldown{}
+	if m != nil {
+		cooldowns = m.Cooldowns()
+	}
+
+	data, err := json.Marshal(cooldowns)
+	if err != nil {
+		http.Error(wr, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	wr.Header().Set("Content-Type", "application/json")
+	wr.Write(data)
+}
+
+// statsHandler shows counts of noteworthy events such as evictions blocked by a PodDisruptionBudget
+func statsHandler(wr http.ResponseWriter, req *http.Request) {
+	wr.Header().Set("Access-Control-Allow-Origin", "*")
+
+	monkeyMutex.RLock()
+	m := monkey
+	monkeyMutex.RUnlock()
+
+	stats := chaoskube.StatsSnapshot{}
+	if m != nil {
+		stats = m.Stats.Snapshot()
+	}
+
+	data, err := json.Marshal(stats)
+	if err != nil {
+		http.Error(wr, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	wr.Header().Set("Content-Type", "application/json")
+	wr.Write(data)
+}
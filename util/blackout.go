@@ -0,0 +1,145 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Blackout is a named range of days during which chaos is suspended. Ranges either refer to
+// particular dates, e.g. 2026-12-18..2027-01-04, or recur annually, e.g. Dec20..Jan5, in which
+// case they may wrap around the end of the year. Both ends of a range are inclusive.
+type Blackout struct {
+	Name string
+	From time.Time
+	To   time.Time
+	// whether the range ignores the year and repeats every year
+	Annual bool
+}
+
+// Includes returns true iff the day of the given point in time falls into the blackout.
+func (b Blackout) Includes(pointInTime time.Time) bool {
+	if b.Annual {
+		day := dayOfYear(pointInTime)
+		from, to := dayOfYear(b.From), dayOfYear(b.To)
+
+		if from <= to {
+			return from <= day && day <= to
+		}
+		return day >= from || day <= to
+	}
+
+	day := time.Date(pointInTime.Year(), pointInTime.Month(), pointInTime.Day(), 0, 0, 0, 0, time.UTC)
+
+	return !day.Before(b.From) && !day.After(b.To)
+}
+
+// String returns the blackout in the format it's parsed from.
+func (b Blackout) String() string {
+	layout := Date
+	if b.Annual {
+		layout = YearDay
+	}
+
+	days := b.From.Format(layout)
+	if !b.To.Equal(b.From) {
+		days = fmt.Sprintf("%s..%s", days, b.To.Format(layout))
+	}
+
+	if b.Name == "" {
+		return days
+	}
+	return fmt.Sprintf("%s=%s", b.Name, days)
+}
+
+// ParseBlackouts takes a comma-separated list of optionally named day ranges, e.g.
+// "freeze=2026-12-18..2027-01-04,holidays=Dec20..Jan5,Apr1", and turns them into a slice of
+// Blackout. A single day is a range that begins and ends on the same day.
+func ParseBlackouts(blackouts string) ([]Blackout, error) {
+	parsedBlackouts := []Blackout{}
+
+	for _, blackout := range strings.Split(blackouts, ",") {
+		if strings.TrimSpace(blackout) == "" {
+			continue
+		}
+
+		parsedBlackout, err := parseBlackout(blackout)
+		if err != nil {
+			return nil, err
+		}
+
+		parsedBlackouts = append(parsedBlackouts, parsedBlackout)
+	}
+
+	return parsedBlackouts, nil
+}
+
+// parseBlackout parses a single, optionally named, range of days.
+func parseBlackout(blackout string) (Blackout, error) {
+	parsedBlackout := Blackout{}
+
+	days := blackout
+	if i := strings.Index(blackout, "="); i >= 0 {
+		parsedBlackout.Name = strings.TrimSpace(blackout[:i])
+		days = blackout[i+1:]
+
+		if parsedBlackout.Name == "" {
+			return Blackout{}, fmt.Errorf("Invalid blackout '%s': empty name", strings.TrimSpace(blackout))
+		}
+	}
+
+	parts := strings.Split(days, "..")
+	if len(parts) > 2 {
+		return Blackout{}, fmt.Errorf("Invalid blackout '%s': too many '..'", strings.TrimSpace(blackout))
+	}
+
+	from, fromAnnual, err := parseBlackoutDay(parts[0])
+	if err != nil {
+		return Blackout{}, err
+	}
+
+	to, toAnnual := from, fromAnnual
+	if len(parts) == 2 {
+		to, toAnnual, err = parseBlackoutDay(parts[1])
+		if err != nil {
+			return Blackout{}, err
+		}
+	}
+
+	if fromAnnual != toAnnual {
+		return Blackout{}, fmt.Errorf("Invalid blackout '%s': either both or none of the days must contain a year", strings.TrimSpace(blackout))
+	}
+
+	// only annual ranges may wrap around the end of the year
+	if !fromAnnual && to.Before(from) {
+		return Blackout{}, fmt.Errorf("Invalid blackout '%s': ends before it begins", strings.TrimSpace(blackout))
+	}
+
+	parsedBlackout.From = from
+	parsedBlackout.To = to
+	parsedBlackout.Annual = fromAnnual
+
+	return parsedBlackout, nil
+}
+
+// parseBlackoutDay parses either a date, e.g. 2026-12-18, or a day of a year, e.g. Dec18, in
+// which case annual is true.
+func parseBlackoutDay(day string) (time.Time, bool, error) {
+	day = strings.TrimSpace(day)
+
+	if parsedDay, err := time.Parse(Date, day); err == nil {
+		return parsedDay, false, nil
+	}
+
+	parsedDay, err := time.Parse(YearDay, day)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("Invalid day '%s': must be either a date like 2026-12-18 or a day of a year like Dec18", day)
+	}
+
+	return parsedDay, true, nil
+}
+
+// dayOfYear returns a comparable representation of the month and day of t.
+func dayOfYear(t time.Time) int {
+	return int(t.Month())*100 + t.Day()
+}
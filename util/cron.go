@@ -0,0 +1,197 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression with the five standard fields: minute, hour, day of month,
+// month and day of week, e.g. "*/15 9-16 * * Mon-Fri".
+type Schedule struct {
+	expr    string
+	minutes uint64
+	hours   uint64
+	days    uint64
+	months  uint64
+	weekday uint64
+	// whether day of month and day of week are both restricted, in which case either may match
+	anyDay bool
+}
+
+// cronField describes the range and names of a single cron field.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	dayField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted as Sunday, too
+	weekdayField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// ParseSchedule parses a standard cron expression with five fields, e.g. "*/15 9-16 * * Mon-Fri".
+// Each field takes *, single values, ranges, steps and comma-separated lists of those. Months and
+// days of week may be given by their English three-letter names, ignoring case.
+func ParseSchedule(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Invalid schedule '%v': expected 5 fields, got %d", expr, len(fields))
+	}
+
+	schedule := &Schedule{expr: strings.Join(fields, " ")}
+
+	for i, field := range []struct {
+		spec  cronField
+		value *uint64
+	}{
+		{minuteField, &schedule.minutes},
+		{hourField, &schedule.hours},
+		{dayField, &schedule.days},
+		{monthField, &schedule.months},
+		{weekdayField, &schedule.weekday},
+	} {
+		bits, err := parseCronField(fields[i], field.spec)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid schedule '%v': %v", expr, err)
+		}
+		*field.value = bits
+	}
+
+	// Sunday can be given as either 0 or 7
+	if schedule.weekday&(1<<7) != 0 {
+		schedule.weekday |= 1
+	}
+
+	// like cron, fields starting with * don't count as restricted, e.g. */2
+	schedule.anyDay = !strings.HasPrefix(fields[2], "*") && !strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+// parseCronField parses a single field of a cron expression into a set of bits.
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			parsedStep, err := strconv.Atoi(part[i+1:])
+			if err != nil || parsedStep <= 0 {
+				return 0, fmt.Errorf("invalid step in %s '%v'", spec.name, part)
+			}
+			rangePart, step = part[:i], parsedStep
+		}
+
+		from, to := spec.min, spec.max
+
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+
+			var err error
+			if from, err = spec.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			to = from
+
+			if len(bounds) == 2 {
+				if to, err = spec.value(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// a single value with a step runs until the end of the range, e.g. 5/15
+				to = spec.max
+			}
+
+			if from > to {
+				return 0, fmt.Errorf("invalid range in %s '%v'", spec.name, part)
+			}
+		}
+
+		for value := from; value <= to; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+// value parses a single value of the field, either a number or a name.
+func (spec cronField) value(value string) (int, error) {
+	if parsed, ok := spec.names[strings.ToLower(value)]; ok {
+		return parsed, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < spec.min || parsed > spec.max {
+		return 0, fmt.Errorf("invalid %s '%v'", spec.name, value)
+	}
+
+	return parsed, nil
+}
+
+// Next returns the first point in time after the given one that matches the schedule, in the
+// given time's location. It returns the zero time if there's none within the next five years,
+// e.g. for Feb 30.
+func (s *Schedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + 5
+
+	for t.Year() <= limit {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchesDay returns true iff the day of the given time matches the schedule. If both day of
+// month and day of week are restricted either of them may match, like cron does.
+func (s *Schedule) matchesDay(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekday&(1<<uint(t.Weekday())) != 0
+
+	if s.anyDay {
+		return day || weekday
+	}
+	return day && weekday
+}
+
+// String returns the cron expression of the schedule.
+func (s *Schedule) String() string {
+	return s.expr
+}
@@ -2,8 +2,10 @@ package util
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
+	"unicode"
 
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,6 +16,8 @@ const (
 	Kitchen24 = "15:04"
 	// a time format that just cares about the day and month.
 	YearDay = "Jan_2"
+	// a time format that cares about the day, month and year.
+	Date = "2006-01-02"
 )
 
 // TimePeriod represents a time period with a single beginning and end.
@@ -41,6 +45,11 @@ func (tp TimePeriod) Includes(pointInTime time.Time) bool {
 	return TimeOfDay(pointInTime).Equal(tp.From)
 }
 
+// CrossesMidnight returns true iff the time period ends on the day after it begins, e.g. 22:00-02:00.
+func (tp TimePeriod) CrossesMidnight() bool {
+	return tp.From.After(tp.To)
+}
+
 // String returns tp as a pretty string.
 func (tp TimePeriod) String() string {
 	return fmt.Sprintf("%s-%s", tp.From.Format(Kitchen24), tp.To.Format(Kitchen24))
@@ -118,6 +127,318 @@ func ParseDays(days string) ([]time.Time, error) {
 	return parsedDays, nil
 }
 
+// TimeWindow represents time periods on particular weekdays, e.g. Mon-Fri 10:00-16:00.
+// No weekdays means every day and no time periods means the whole day.
+type TimeWindow struct {
+	Weekdays    []time.Weekday
+	TimePeriods []TimePeriod
+}
+
+// Includes returns true iff the given point in time falls into the window. Time periods crossing
+// midnight belong to the weekday they begin on, e.g. Fri 22:00-02:00 includes early Saturday.
+func (tw TimeWindow) Includes(pointInTime time.Time) bool {
+	if len(tw.TimePeriods) == 0 {
+		return tw.includesWeekday(pointInTime.Weekday())
+	}
+
+	for _, tp := range tw.TimePeriods {
+		if !tp.Includes(pointInTime) {
+			continue
+		}
+
+		weekday := pointInTime.Weekday()
+		if tp.CrossesMidnight() && TimeOfDay(pointInTime).Before(tp.To) {
+			// the part after midnight belongs to the day before
+			weekday = (weekday + 6) % 7
+		}
+
+		if tw.includesWeekday(weekday) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// includesWeekday returns true iff the window applies to the given weekday.
+func (tw TimeWindow) includesWeekday(weekday time.Weekday) bool {
+	return len(tw.Weekdays) == 0 || containsWeekday(tw.Weekdays, weekday)
+}
+
+// ParseWeekdayTimePeriods takes a semicolon-separated list of day-specific time periods (e.g.
+// "Fri 14:00-23:59;Mon 00:00-10:00") and turns them into a slice of TimeWindow. Unlike
+// ParseTimeWindows each window must name its weekdays and time periods.
+func ParseWeekdayTimePeriods(periods string) ([]TimeWindow, error) {
+	windows, err := ParseTimeWindows(periods)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, window := range windows {
+		if len(window.Weekdays) == 0 || len(window.TimePeriods) == 0 {
+			return nil, fmt.Errorf("Invalid weekday time period '%v': must contain weekdays and time periods", window)
+		}
+	}
+
+	return windows, nil
+}
+
+// String returns the window in the format it's parsed from.
+func (tw TimeWindow) String() string {
+	parts := []string{}
+
+	if len(tw.Weekdays) > 0 {
+		days := make([]string, 0, len(tw.Weekdays))
+		for _, wd := range tw.Weekdays {
+			days = append(days, wd.String()[:3])
+		}
+		parts = append(parts, strings.Join(days, ","))
+	}
+
+	if len(tw.TimePeriods) > 0 {
+		periods := make([]string, 0, len(tw.TimePeriods))
+		for _, tp := range tw.TimePeriods {
+			periods = append(periods, tp.String())
+		}
+		parts = append(parts, strings.Join(periods, ","))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// ParseTimeWindows takes a semicolon-separated list of time windows (e.g. "Mon-Fri 10:00-16:00;Sat
+// 10:00-12:00") and turns them into a slice of TimeWindow. Each window consists of optional weekdays,
+// given as a comma-separated list of days or ranges of days, and optional time periods as understood
+// by ParseTimePeriods. It ignores any whitespace and case.
+func ParseTimeWindows(windows string) ([]TimeWindow, error) {
+	parsedWindows := []TimeWindow{}
+
+	for _, window := range strings.Split(windows, ";") {
+		window = strings.TrimSpace(window)
+		if window == "" {
+			continue
+		}
+
+		weekdays, timePeriods := "", window
+		if first := window[0]; (first >= 'a' && first <= 'z') || (first >= 'A' && first <= 'Z') {
+			weekdays, timePeriods = window, ""
+			if i := strings.IndexFunc(window, unicode.IsSpace); i >= 0 {
+				weekdays, timePeriods = window[:i], window[i:]
+			}
+		}
+
+		parsedWeekdays, err := parseWeekdayRanges(weekdays)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid time window '%v': %v", window, err)
+		}
+
+		parsedTimePeriods, err := ParseTimePeriods(timePeriods)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid time window '%v': %v", window, err)
+		}
+
+		parsedWindows = append(parsedWindows, TimeWindow{Weekdays: parsedWeekdays, TimePeriods: parsedTimePeriods})
+	}
+
+	return parsedWindows, nil
+}
+
+// parseWeekdayRanges takes a comma-separated list of weekdays and ranges of weekdays (e.g.
+// Mon-Wed,Fri) and turns them into a slice of time.Weekday. Ranges may wrap around the end of
+// the week, e.g. Sat-Mon. Unlike ParseWeekdays it fails on unknown weekdays.
+func parseWeekdayRanges(weekdays string) ([]time.Weekday, error) {
+	parsedWeekdays := []time.Weekday{}
+
+	for _, wd := range strings.Split(weekdays, ",") {
+		if strings.TrimSpace(wd) == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(wd, "-", 2)
+
+		from := ParseWeekdays(bounds[0])
+		to := from
+		if len(bounds) == 2 {
+			to = ParseWeekdays(bounds[1])
+		}
+
+		if len(from) != 1 || len(to) != 1 {
+			return nil, fmt.Errorf("invalid weekday '%v'", strings.TrimSpace(wd))
+		}
+
+		for day := from[0]; ; day = (day + 1) % 7 {
+			if !containsWeekday(parsedWeekdays, day) {
+				parsedWeekdays = append(parsedWeekdays, day)
+			}
+			if day == to[0] {
+				break
+			}
+		}
+	}
+
+	return parsedWeekdays, nil
+}
+
+// containsWeekday returns true iff the weekday is in the given list.
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, wd := range weekdays {
+		if wd == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePodPhases takes a comma-separated list of pod phases (e.g. Running,Pending) and turns them
+// into a slice of v1.PodPhase. It ignores any whitespace and case.
+func ParsePodPhases(phases string) ([]v1.PodPhase, error) {
+	var knownPhases = map[string]v1.PodPhase{
+		"pending":   v1.PodPending,
+		"running":   v1.PodRunning,
+		"succeeded": v1.PodSucceeded,
+		"failed":    v1.PodFailed,
+		"unknown":   v1.PodUnknown,
+	}
+
+	parsedPhases := []v1.PodPhase{}
+
+	for _, phase := range strings.Split(phases, ",") {
+		if strings.TrimSpace(phase) == "" {
+			continue
+		}
+
+		parsedPhase, ok := knownPhases[strings.TrimSpace(strings.ToLower(phase))]
+		if !ok {
+			return nil, fmt.Errorf("Invalid pod phase '%v'", phase)
+		}
+
+		parsedPhases = append(parsedPhases, parsedPhase)
+	}
+
+	return parsedPhases, nil
+}
+
+// ParseQOSClasses takes a comma-separated list of QoS classes (e.g. BestEffort,Burstable) and turns
+// them into a slice of v1.PodQOSClass. It ignores any whitespace and case.
+func ParseQOSClasses(classes string) ([]v1.PodQOSClass, error) {
+	var knownClasses = map[string]v1.PodQOSClass{
+		"guaranteed": v1.PodQOSGuaranteed,
+		"burstable":  v1.PodQOSBurstable,
+		"besteffort": v1.PodQOSBestEffort,
+	}
+
+	parsedClasses := []v1.PodQOSClass{}
+
+	for _, class := range strings.Split(classes, ",") {
+		if strings.TrimSpace(class) == "" {
+			continue
+		}
+
+		parsedClass, ok := knownClasses[strings.TrimSpace(strings.ToLower(class))]
+		if !ok {
+			return nil, fmt.Errorf("Invalid QoS class '%v'", class)
+		}
+
+		parsedClasses = append(parsedClasses, parsedClass)
+	}
+
+	return parsedClasses, nil
+}
+
+// ParseList takes a comma-separated list of names (e.g. DaemonSet,Job) and turns them into a slice
+// of strings. It ignores any whitespace and empty names.
+func ParseList(list string) []string {
+	parsedList := []string{}
+
+	for _, item := range strings.Split(list, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+
+		parsedList = append(parsedList, strings.TrimSpace(item))
+	}
+
+	return parsedList
+}
+
+// NamePattern matches names against a glob, e.g. team-*-staging, or a regular expression
+// starting with ^, e.g. ^batch-.*. Excluding patterns are prefixed with !.
+type NamePattern struct {
+	Pattern string
+	Exclude bool
+	regexp  *regexp.Regexp
+}
+
+// Matches returns true iff the name matches the pattern, regardless of whether it's excluding.
+func (np NamePattern) Matches(name string) bool {
+	return np.regexp.MatchString(name)
+}
+
+// String returns the pattern as it was given.
+func (np NamePattern) String() string {
+	if np.Exclude {
+		return "!" + np.Pattern
+	}
+	return np.Pattern
+}
+
+// ParseNamePatterns parses a comma-separated list of name patterns, e.g. "team-*,!*-prod".
+func ParseNamePatterns(patterns string) ([]NamePattern, error) {
+	parsedPatterns := []NamePattern{}
+
+	for _, pattern := range strings.Split(patterns, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		namePattern := NamePattern{}
+		if strings.HasPrefix(pattern, "!") {
+			namePattern.Exclude = true
+			pattern = strings.TrimSpace(strings.TrimPrefix(pattern, "!"))
+		}
+		namePattern.Pattern = pattern
+
+		expr := pattern
+		if !strings.HasPrefix(pattern, "^") {
+			// anchor the glob and turn its wildcards into their regexp equivalents
+			expr = "^" + strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(pattern)) + "$"
+		}
+
+		parsedExpr, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid name pattern '%v': %v", pattern, err)
+		}
+		namePattern.regexp = parsedExpr
+
+		parsedPatterns = append(parsedPatterns, namePattern)
+	}
+
+	return parsedPatterns, nil
+}
+
+// MatchesNamePatterns returns true iff the name matches at least one including pattern, if
+// there are any, and none of the excluding patterns.
+func MatchesNamePatterns(patterns []NamePattern, name string) bool {
+	included, hasIncludes := false, false
+
+	for _, pattern := range patterns {
+		if pattern.Exclude {
+			if pattern.Matches(name) {
+				return false
+			}
+			continue
+		}
+
+		hasIncludes = true
+		if pattern.Matches(name) {
+			included = true
+		}
+	}
+
+	return included || !hasIncludes
+}
+
 // TimeOfDay normalizes the given point in time by returning a time object that represents the same
 // time of day of the given time but on the very first day (day 0).
 func TimeOfDay(pointInTime time.Time) time.Time {
@@ -137,5 +458,11 @@ func NewPod(namespace, name string) v1.Pod {
 				"chaos": name,
 			},
 		},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionTrue},
+			},
+		},
 	}
 }
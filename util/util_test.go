@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/suite"
+
+	"k8s.io/api/core/v1"
 )
 
 type Suite struct {
@@ -365,6 +367,435 @@ func (suite *Suite) TestParseDates() {
 	}
 }
 
+func (suite *Suite) TestParseTimeWindows() {
+	for _, tt := range []struct {
+		given    string
+		expected []string
+	}{
+		// empty string
+		{
+			"",
+			[]string{},
+		},
+		// weekdays and time periods
+		{
+			"Mon-Fri 10:00-16:00",
+			[]string{"Mon,Tue,Wed,Thu,Fri 10:00-16:00"},
+		},
+		// weekdays only, wrapping around the end of the week
+		{
+			"sat-MON",
+			[]string{"Sat,Sun,Mon"},
+		},
+		// time periods only
+		{
+			"10:00-12:00,14:00-16:00",
+			[]string{"10:00-12:00,14:00-16:00"},
+		},
+		// multiple windows ignoring whitespace
+		{
+			" Mon,Wed-Thu  10:00 - 16:00 ;; Sat 10:00-12:00 ",
+			[]string{"Mon,Wed,Thu 10:00-16:00", "Sat 10:00-12:00"},
+		},
+	} {
+		windows, err := ParseTimeWindows(tt.given)
+		suite.Require().NoError(err)
+
+		parsed := []string{}
+		for _, window := range windows {
+			parsed = append(parsed, window.String())
+		}
+		suite.Equal(tt.expected, parsed)
+	}
+
+	for _, tt := range []string{
+		"Funday 10:00-16:00",
+		"Mon-Funday",
+		"Mon 10:00",
+		"Mon 25:00-26:00",
+	} {
+		_, err := ParseTimeWindows(tt)
+		suite.Error(err, tt)
+	}
+}
+
+func (suite *Suite) TestTimeWindowIncludes() {
+	// a Friday
+	friday := time.Date(1869, 9, 24, 15, 04, 05, 06, time.UTC)
+
+	for _, tt := range []struct {
+		window      string
+		pointInTime time.Time
+		expected    bool
+	}{
+		// the whole window matches
+		{"Mon-Fri 10:00-16:00", friday, true},
+		// wrong weekday
+		{"Mon-Thu 10:00-16:00", friday, false},
+		// wrong time of day
+		{"Mon-Fri 10:00-15:00", friday, false},
+		// any of the time periods matches
+		{"Fri 08:00-09:00,15:00-16:00", friday, true},
+		// weekdays only match the whole day
+		{"Fri", friday, true},
+		{"Sat", friday, false},
+		// time periods only match every day
+		{"15:00-16:00", friday.Add(24 * time.Hour), true},
+		// periods crossing midnight belong to the weekday they start on
+		{"Fri 22:00-02:00", friday.Add(10 * time.Hour), true},
+		{"Thu 22:00-02:00", friday.Add(-14 * time.Hour), true},
+		{"Fri 22:00-02:00", friday.Add(-14 * time.Hour), false},
+	} {
+		windows, err := ParseTimeWindows(tt.window)
+		suite.Require().NoError(err)
+		suite.Require().Len(windows, 1)
+
+		suite.Equal(tt.expected, windows[0].Includes(tt.pointInTime), tt.window)
+	}
+}
+
+func (suite *Suite) TestParseWeekdayTimePeriods() {
+	windows, err := ParseWeekdayTimePeriods("Fri 14:00-23:59;Mon 00:00-10:00")
+	suite.Require().NoError(err)
+
+	parsed := []string{}
+	for _, window := range windows {
+		parsed = append(parsed, window.String())
+	}
+	suite.Equal([]string{"Fri 14:00-23:59", "Mon 00:00-10:00"}, parsed)
+
+	for _, tt := range []string{
+		"14:00-23:59",
+		"Fri",
+		"Fri 14:00-25:00",
+	} {
+		_, err := ParseWeekdayTimePeriods(tt)
+		suite.Error(err, tt)
+	}
+}
+
+func (suite *Suite) TestParseBlackouts() {
+	for _, tt := range []struct {
+		given    string
+		expected []string
+	}{
+		// empty string
+		{
+			"",
+			[]string{},
+		},
+		// named and unnamed ranges ignoring whitespace
+		{
+			" freeze = 2026-12-18..2027-01-04 ,, holidays=Dec20..Jan5, Apr1 ",
+			[]string{"freeze=2026-12-18..2027-01-04", "holidays=Dec20..Jan 5", "Apr 1"},
+		},
+		// single dates
+		{
+			"2026-11-27..2026-11-27",
+			[]string{"2026-11-27"},
+		},
+	} {
+		blackouts, err := ParseBlackouts(tt.given)
+		suite.Require().NoError(err)
+
+		parsed := []string{}
+		for _, blackout := range blackouts {
+			parsed = append(parsed, blackout.String())
+		}
+		suite.Equal(tt.expected, parsed)
+	}
+
+	for _, tt := range []string{
+		"Dec32",
+		"=Dec24",
+		"Dec20..Jan5..Feb1",
+		"2026-12-18..Jan4",
+		"2027-01-04..2026-12-18",
+		"2026-13-01",
+	} {
+		_, err := ParseBlackouts(tt)
+		suite.Error(err, tt)
+	}
+}
+
+func (suite *Suite) TestBlackoutIncludes() {
+	for _, tt := range []struct {
+		blackout    string
+		pointInTime time.Time
+		expected    bool
+	}{
+		// within a range of dates, both ends inclusive
+		{"2026-12-18..2027-01-04", time.Date(2026, 12, 18, 0, 0, 0, 0, time.UTC), true},
+		{"2026-12-18..2027-01-04", time.Date(2027, 1, 4, 23, 59, 0, 0, time.UTC), true},
+		// outside of a range of dates
+		{"2026-12-18..2027-01-04", time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC), false},
+		// the same days in another year
+		{"2026-12-18..2027-01-04", time.Date(2027, 12, 20, 0, 0, 0, 0, time.UTC), false},
+		// the day is taken from the point in time's own timezone
+		{"2026-11-27", time.Date(2026, 11, 27, 23, 0, 0, 0, time.FixedZone("UTC-5", -5*3600)), true},
+		// annual ranges repeat every year
+		{"Nov23..Nov30", time.Date(1869, 11, 25, 0, 0, 0, 0, time.UTC), true},
+		{"Nov23..Nov30", time.Date(1869, 12, 1, 0, 0, 0, 0, time.UTC), false},
+		// annual ranges wrap around the end of the year
+		{"Dec20..Jan5", time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), true},
+		{"Dec20..Jan5", time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC), true},
+		{"Dec20..Jan5", time.Date(2027, 1, 6, 0, 0, 0, 0, time.UTC), false},
+		{"Dec20..Jan5", time.Date(2026, 12, 19, 0, 0, 0, 0, time.UTC), false},
+	} {
+		blackouts, err := ParseBlackouts(tt.blackout)
+		suite.Require().NoError(err)
+		suite.Require().Len(blackouts, 1)
+
+		suite.Equal(tt.expected, blackouts[0].Includes(tt.pointInTime), tt.blackout)
+	}
+}
+
+func (suite *Suite) TestParsePodPhases() {
+	for _, tt := range []struct {
+		given    string
+		expected []v1.PodPhase
+	}{
+		// empty string
+		{
+			"",
+			[]v1.PodPhase{},
+		},
+		// single phase
+		{
+			"Running",
+			[]v1.PodPhase{v1.PodRunning},
+		},
+		// multiple phases ignoring case and whitespace
+		{
+			" running ,, PENDING ",
+			[]v1.PodPhase{v1.PodRunning, v1.PodPending},
+		},
+	} {
+		phases, err := ParsePodPhases(tt.given)
+		suite.Require().NoError(err)
+
+		suite.Equal(tt.expected, phases)
+	}
+
+	_, err := ParsePodPhases("Running,Sleeping")
+	suite.Error(err)
+}
+
+func (suite *Suite) TestParseQOSClasses() {
+	for _, tt := range []struct {
+		given    string
+		expected []v1.PodQOSClass
+	}{
+		// empty string
+		{
+			"",
+			[]v1.PodQOSClass{},
+		},
+		// multiple classes ignoring case and whitespace
+		{
+			" besteffort ,, Burstable ",
+			[]v1.PodQOSClass{v1.PodQOSBestEffort, v1.PodQOSBurstable},
+		},
+	} {
+		classes, err := ParseQOSClasses(tt.given)
+		suite.Require().NoError(err)
+
+		suite.Equal(tt.expected, classes)
+	}
+
+	_, err := ParseQOSClasses("Guaranteed,Platinum")
+	suite.Error(err)
+}
+
+func (suite *Suite) TestParseList() {
+	for _, tt := range []struct {
+		given    string
+		expected []string
+	}{
+		// empty string
+		{
+			"",
+			[]string{},
+		},
+		// ignore whitespace and empty items
+		{
+			" DaemonSet ,, Job ",
+			[]string{"DaemonSet", "Job"},
+		},
+	} {
+		suite.Equal(tt.expected, ParseList(tt.given))
+	}
+}
+
+func (suite *Suite) TestParseNamePatterns() {
+	for _, tt := range []struct {
+		given    string
+		expected []string
+	}{
+		// empty string
+		{
+			"",
+			[]string{},
+		},
+		// globs and regexps ignoring whitespace
+		{
+			" team-*-staging ,, !^batch-.* ",
+			[]string{"team-*-staging", "!^batch-.*"},
+		},
+	} {
+		patterns, err := ParseNamePatterns(tt.given)
+		suite.Require().NoError(err)
+
+		parsed := []string{}
+		for _, pattern := range patterns {
+			parsed = append(parsed, pattern.String())
+		}
+		suite.Equal(tt.expected, parsed)
+	}
+
+	_, err := ParseNamePatterns("^batch-(")
+	suite.Error(err)
+}
+
+func (suite *Suite) TestMatchesNamePatterns() {
+	for _, tt := range []struct {
+		patterns string
+		name     string
+		expected bool
+	}{
+		// no patterns match everything
+		{"", "foo", true},
+		// globs match the whole name
+		{"team-*-staging", "team-a-staging", true},
+		{"team-*-staging", "team-a-staging-2", false},
+		{"team-?", "team-a", true},
+		{"team-?", "team-ab", false},
+		// dots in globs are no wildcards
+		{"foo.bar", "fooxbar", false},
+		// regexps match as given
+		{"^batch-.*", "batch-1", true},
+		{"^batch-.*", "my-batch-1", false},
+		// any including pattern matches
+		{"foo,bar", "bar", true},
+		{"foo,bar", "baz", false},
+		// excluding patterns only
+		{"!foo", "foo", false},
+		{"!foo", "bar", true},
+		// excluding patterns win
+		{"team-*,!*-prod", "team-a-prod", false},
+		{"team-*,!*-prod", "team-a-staging", true},
+	} {
+		patterns, err := ParseNamePatterns(tt.patterns)
+		suite.Require().NoError(err)
+
+		suite.Equal(tt.expected, MatchesNamePatterns(patterns, tt.name), "%s %s", tt.patterns, tt.name)
+	}
+}
+
+func (suite *Suite) TestParseSchedule() {
+	for _, tt := range []string{
+		"* * * * *",
+		"*/15 9-16 * * Mon-Fri",
+		"0,30 8-18/2 1-15 jan-MAR,dec sun,7",
+		"5/10 * * * *",
+	} {
+		schedule, err := ParseSchedule(tt)
+		suite.Require().NoError(err, tt)
+		suite.Equal(tt, schedule.String())
+	}
+
+	for _, tt := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 * ",
+		"* * * * 8",
+		"* * * * Funday",
+		"16-9 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+	} {
+		_, err := ParseSchedule(tt)
+		suite.Error(err, tt)
+	}
+}
+
+func (suite *Suite) TestScheduleNext() {
+	timezone, err := time.LoadLocation("Australia/Brisbane")
+	suite.Require().NoError(err)
+
+	// a Friday
+	friday := time.Date(1869, 9, 24, 15, 04, 05, 06, time.UTC)
+
+	for _, tt := range []struct {
+		schedule string
+		after    time.Time
+		expected time.Time
+	}{
+		// every minute
+		{
+			"* * * * *",
+			friday,
+			time.Date(1869, 9, 24, 15, 05, 00, 00, time.UTC),
+		},
+		// strictly after the given time
+		{
+			"5 * * * *",
+			time.Date(1869, 9, 24, 15, 05, 00, 00, time.UTC),
+			time.Date(1869, 9, 24, 16, 05, 00, 00, time.UTC),
+		},
+		// every 15 minutes during office hours
+		{
+			"*/15 9-16 * * Mon-Fri",
+			friday,
+			time.Date(1869, 9, 24, 15, 15, 00, 00, time.UTC),
+		},
+		// skips to the next week day after office hours on a Friday
+		{
+			"*/15 9-16 * * Mon-Fri",
+			time.Date(1869, 9, 24, 16, 50, 00, 00, time.UTC),
+			time.Date(1869, 9, 27, 9, 00, 00, 00, time.UTC),
+		},
+		// either day of month or day of week match if both are restricted
+		{
+			"0 0 1 * Sun",
+			friday,
+			time.Date(1869, 9, 26, 0, 00, 00, 00, time.UTC),
+		},
+		// skips to the next matching month and year
+		{
+			"0 12 24 Dec *",
+			friday,
+			time.Date(1869, 12, 24, 12, 00, 00, 00, time.UTC),
+		},
+		{
+			"0 0 1 Jan *",
+			friday,
+			time.Date(1870, 1, 1, 0, 00, 00, 00, time.UTC),
+		},
+		// it's evaluated in the given time's location
+		{
+			"0 9 * * *",
+			time.Date(1869, 9, 24, 15, 04, 05, 06, timezone),
+			time.Date(1869, 9, 25, 9, 00, 00, 00, timezone),
+		},
+		// impossible dates never match
+		{
+			"0 0 30 Feb *",
+			friday,
+			time.Time{},
+		},
+	} {
+		schedule, err := ParseSchedule(tt.schedule)
+		suite.Require().NoError(err)
+
+		suite.Equal(tt.expected, schedule.Next(tt.after), tt.schedule)
+	}
+}
+
 func TestSuite(t *testing.T) {
 	suite.Run(t, new(Suite))
 }